@@ -10,3 +10,4 @@ geas -bin -no-push0 contracts/emit.eas > bytecode/emit.bin
 geas -bin -no-push0 contracts/genlogs.eas > bytecode/genlogs.bin
 geas -bin -no-push0 contracts/gencode.eas > bytecode/gencode.bin
 geas -bin -no-push0 contracts/genstorage.eas > bytecode/genstorage.bin
+geas -bin -no-push0 contracts/fillstorage.eas > bytecode/fillstorage.bin
@@ -11,6 +11,9 @@ var genlogsCode []byte
 //go:embed bytecode/genstorage.bin
 var genstorageCode []byte
 
+//go:embed bytecode/fillstorage.bin
+var fillstorageCode []byte
+
 //go:embed bytecode/deployer.bin
 var deployerCode []byte
 
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// This file implements the era1 archive format for pre-merge blocks.
+//
+// An era1 file is an e2store file with this structure:
+//
+//	Version | block-tuple* | Accumulator | BlockIndex
+//	block-tuple = CompressedHeader | CompressedBody | CompressedReceipts | TotalDifficulty
+
+const (
+	e2Version            = 0x3265
+	e2CompressedHeader   = 0x03
+	e2CompressedBody     = 0x04
+	e2CompressedReceipts = 0x05
+	e2TotalDifficulty    = 0x06
+	e2Accumulator        = 0x07
+	e2BlockIndex         = 0x3266
+
+	e2HeaderSize = 8
+	era1MaxSize  = 8192 // maximum number of blocks in an era1 file
+)
+
+// era1Builder writes an era1 file.
+type era1Builder struct {
+	w       io.Writer
+	written uint64
+
+	startNum uint64
+	offsets  []uint64
+	hashes   []common.Hash
+	tds      []*big.Int
+}
+
+func newEra1Builder(w io.Writer) *era1Builder {
+	return &era1Builder{w: w}
+}
+
+// add writes a block to the file. Blocks must be added in ascending order.
+func (b *era1Builder) add(block *types.Block, receipts types.Receipts, td *big.Int) error {
+	if len(b.offsets) == 0 {
+		if err := b.writeEntry(e2Version, nil); err != nil {
+			return err
+		}
+		b.startNum = block.NumberU64()
+	}
+	if len(b.offsets) >= era1MaxSize {
+		return fmt.Errorf("era1 file can't contain more than %d blocks", era1MaxSize)
+	}
+	if want := b.startNum + uint64(len(b.offsets)); block.NumberU64() != want {
+		return fmt.Errorf("era1: got block %d, want %d", block.NumberU64(), want)
+	}
+	if receipts == nil {
+		receipts = types.Receipts{}
+	}
+	b.offsets = append(b.offsets, b.written)
+	b.hashes = append(b.hashes, block.Hash())
+	b.tds = append(b.tds, new(big.Int).Set(td))
+
+	body := &types.Body{Transactions: block.Transactions(), Uncles: block.Uncles()}
+	for _, e := range []struct {
+		typ uint16
+		val any
+	}{
+		{e2CompressedHeader, block.Header()},
+		{e2CompressedBody, body},
+		{e2CompressedReceipts, receipts},
+	} {
+		enc, err := rlp.EncodeToBytes(e.val)
+		if err != nil {
+			return err
+		}
+		if err := b.writeEntry(e.typ, snappyEncode(enc)); err != nil {
+			return err
+		}
+	}
+	return b.writeEntry(e2TotalDifficulty, uint256LE(td))
+}
+
+// finish writes the accumulator and block index. It returns the accumulator root.
+func (b *era1Builder) finish() (common.Hash, error) {
+	if len(b.offsets) == 0 {
+		return common.Hash{}, errors.New("era1: no blocks added")
+	}
+	root := era1Accumulator(b.hashes, b.tds)
+	if err := b.writeEntry(e2Accumulator, root[:]); err != nil {
+		return common.Hash{}, err
+	}
+
+	// Offsets in the index are relative to the start of the index entry.
+	base := b.written
+	index := make([]byte, 16+8*len(b.offsets))
+	binary.LittleEndian.PutUint64(index, b.startNum)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(int64(offset)-int64(base)))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*len(b.offsets):], uint64(len(b.offsets)))
+	return root, b.writeEntry(e2BlockIndex, index)
+}
+
+func (b *era1Builder) writeEntry(typ uint16, data []byte) error {
+	var header [e2HeaderSize]byte
+	binary.LittleEndian.PutUint16(header[0:], typ)
+	binary.LittleEndian.PutUint32(header[2:], uint32(len(data)))
+	if _, err := b.w.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := b.w.Write(data); err != nil {
+		return err
+	}
+	b.written += uint64(e2HeaderSize + len(data))
+	return nil
+}
+
+// era1Block is a block read from an era1 file.
+type era1Block struct {
+	Block    *types.Block
+	Receipts types.Receipts
+	TD       *big.Int
+}
+
+// readEra1 reads all blocks from an era1 file. It also checks the block index and
+// accumulator, and returns the accumulator root.
+func readEra1(file string) ([]era1Block, common.Hash, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	var (
+		entries []e2Entry
+		pos     uint64
+	)
+	for pos < uint64(len(data)) {
+		e, err := readE2Entry(data, pos)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		entries = append(entries, e)
+		pos += e2HeaderSize + uint64(len(e.data))
+	}
+
+	// Check the file structure.
+	if len(entries) < 7 || entries[0].typ != e2Version {
+		return nil, common.Hash{}, errors.New("era1: invalid file structure")
+	}
+	tuples := entries[1 : len(entries)-2]
+	accEntry, indexEntry := entries[len(entries)-2], entries[len(entries)-1]
+	if len(tuples)%4 != 0 || accEntry.typ != e2Accumulator || indexEntry.typ != e2BlockIndex {
+		return nil, common.Hash{}, errors.New("era1: invalid file structure")
+	}
+	count := len(tuples) / 4
+	if len(indexEntry.data) != 16+8*count || binary.LittleEndian.Uint64(indexEntry.data[8+8*count:]) != uint64(count) {
+		return nil, common.Hash{}, errors.New("era1: invalid block index")
+	}
+	startNum := binary.LittleEndian.Uint64(indexEntry.data)
+
+	var (
+		blocks = make([]era1Block, count)
+		hashes = make([]common.Hash, count)
+		tds    = make([]*big.Int, count)
+	)
+	for i := range blocks {
+		t := tuples[i*4 : i*4+4]
+		rel := int64(binary.LittleEndian.Uint64(indexEntry.data[8+8*i:]))
+		if uint64(int64(indexEntry.offset)+rel) != t[0].offset {
+			return nil, common.Hash{}, fmt.Errorf("era1: wrong index offset of block %d", i)
+		}
+		b, err := decodeEra1Tuple(t)
+		if err != nil {
+			return nil, common.Hash{}, fmt.Errorf("era1: block %d: %v", i, err)
+		}
+		if b.Block.NumberU64() != startNum+uint64(i) {
+			return nil, common.Hash{}, fmt.Errorf("era1: block %d has number %d", i, b.Block.NumberU64())
+		}
+		blocks[i] = b
+		hashes[i] = b.Block.Hash()
+		tds[i] = b.TD
+	}
+	root := era1Accumulator(hashes, tds)
+	if !bytes.Equal(accEntry.data, root[:]) {
+		return nil, common.Hash{}, errors.New("era1: accumulator mismatch")
+	}
+	return blocks, root, nil
+}
+
+func decodeEra1Tuple(t []e2Entry) (era1Block, error) {
+	var (
+		b      era1Block
+		header types.Header
+		body   types.Body
+	)
+	if t[0].typ != e2CompressedHeader || t[1].typ != e2CompressedBody || t[2].typ != e2CompressedReceipts || t[3].typ != e2TotalDifficulty {
+		return b, errors.New("invalid block tuple")
+	}
+	for _, e := range []struct {
+		data []byte
+		val  any
+	}{
+		{t[0].data, &header},
+		{t[1].data, &body},
+		{t[2].data, &b.Receipts},
+	} {
+		dec, err := snappyDecode(e.data)
+		if err != nil {
+			return b, err
+		}
+		if err := rlp.DecodeBytes(dec, e.val); err != nil {
+			return b, err
+		}
+	}
+	if len(t[3].data) != 32 {
+		return b, errors.New("invalid total difficulty")
+	}
+	b.Block = types.NewBlockWithHeader(&header).WithBody(body.Transactions, body.Uncles)
+	b.TD = new(big.Int).SetBytes(reverse(t[3].data))
+	return b, nil
+}
+
+type e2Entry struct {
+	offset uint64
+	typ    uint16
+	data   []byte
+}
+
+func readE2Entry(data []byte, pos uint64) (e2Entry, error) {
+	if uint64(len(data))-pos < e2HeaderSize {
+		return e2Entry{}, fmt.Errorf("e2store: truncated entry header at offset %d", pos)
+	}
+	header := data[pos : pos+e2HeaderSize]
+	length := uint64(binary.LittleEndian.Uint32(header[2:]))
+	if header[6] != 0 || header[7] != 0 {
+		return e2Entry{}, fmt.Errorf("e2store: non-zero reserved bytes at offset %d", pos)
+	}
+	if uint64(len(data))-pos-e2HeaderSize < length {
+		return e2Entry{}, fmt.Errorf("e2store: truncated entry at offset %d", pos)
+	}
+	return e2Entry{
+		offset: pos,
+		typ:    binary.LittleEndian.Uint16(header),
+		data:   data[pos+e2HeaderSize : pos+e2HeaderSize+length],
+	}, nil
+}
+
+// era1Accumulator computes the SSZ hash tree root of List[HeaderRecord, 8192], where
+// HeaderRecord is the container {block_hash: Bytes32, total_difficulty: uint256}.
+func era1Accumulator(hashes []common.Hash, tds []*big.Int) common.Hash {
+	layer := make([]common.Hash, len(hashes))
+	for i := range hashes {
+		layer[i] = sha256.Sum256(append(hashes[i].Bytes(), uint256LE(tds[i])...))
+	}
+	// Merkleize with the list limit, using zero hashes for the empty subtrees.
+	var zero common.Hash
+	for size := era1MaxSize; size > 1; size /= 2 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zero)
+		}
+		next := make([]common.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(layer[2*i].Bytes(), layer[2*i+1].Bytes()...))
+		}
+		layer = next
+		zero = sha256.Sum256(append(zero.Bytes(), zero.Bytes()...))
+	}
+	root := zero
+	if len(layer) > 0 {
+		root = layer[0]
+	}
+	// Mix in the list length.
+	var length [32]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(hashes)))
+	return sha256.Sum256(append(root.Bytes(), length[:]...))
+}
+
+// uint256LE encodes an integer as a 32-byte little-endian value.
+func uint256LE(v *big.Int) []byte {
+	var b [32]byte
+	v.FillBytes(b[:])
+	return reverse(b[:])
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func snappyEncode(data []byte) []byte {
+	var buf bytes.Buffer
+	w := snappy.NewBufferedWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func snappyDecode(data []byte) ([]byte, error) {
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/exp/slices"
 )
@@ -28,14 +29,31 @@ import (
 // generatorConfig is the configuration of the chain generator.
 type generatorConfig struct {
 	// genesis options
-	forkInterval int    // number of blocks between forks
-	lastFork     string // last enabled fork
-	clique       bool   // create a clique chain
+	forkInterval int                 // number of blocks between forks
+	lastFork     string              // last enabled fork
+	forks        map[string]forkSpec // explicit fork schedule, overrides forkInterval/lastFork
+	clique       bool                // create a clique chain
+	chainID      *big.Int            // chain ID
+	gasLimit     uint64              // genesis block gas limit
+	alloc        core.GenesisAlloc   // additional genesis accounts
 
 	// chain options
-	txInterval  int // frequency of blocks containing transactions
-	txCount     int // number of txs in block
-	chainLength int // number of generated blocks
+	txInterval  int             // frequency of blocks containing transactions
+	txCount     int             // number of txs in block
+	chainLength int             // number of generated blocks
+	blockTime   uint64          // seconds between blocks
+	seed        int64           // random seed
+	modifiers   []modifierRange // modifier selection, all modifiers are used if empty
+
+	// state options
+	stateAccounts  int // number of accounts created by state-accounts
+	stateSlots     int // storage slots per contract of state-storage and state-selfdestruct
+	stateContracts int // number of contracts filled by state-storage and state-selfdestruct
+	stateBigCode   int // number of contracts created by state-bigcode
+
+	// side chain options
+	sideChainForks  []uint64 // fork block numbers of side chains
+	sideChainLength int      // number of blocks in each side chain
 
 	// output options
 	outputs   []string // enabled outputs
@@ -49,9 +67,40 @@ func (cfg generatorConfig) withDefaults() (generatorConfig, error) {
 	if cfg.txInterval == 0 {
 		cfg.txInterval = 1
 	}
+	if cfg.chainID == nil {
+		cfg.chainID = new(big.Int).Set(defaultChainID)
+	}
+	if cfg.gasLimit == 0 {
+		cfg.gasLimit = params.GenesisGasLimit * 8
+	}
+	if cfg.blockTime == 0 {
+		cfg.blockTime = blocktimeSec
+	}
+	if cfg.blockTime < minBlockTime {
+		return cfg, fmt.Errorf("block time %ds is too low, minimum is %ds", cfg.blockTime, minBlockTime)
+	}
+	if cfg.seed == 0 {
+		cfg.seed = defaultSeed
+	}
+	if err := cfg.resolveForks(); err != nil {
+		return cfg, err
+	}
 	if cfg.outputs == nil {
 		cfg.outputs = []string{"genesis", "chain", "txinfo"}
 	}
+	if len(cfg.sideChainForks) > 0 {
+		if cfg.sideChainLength == 0 {
+			cfg.sideChainLength = 1
+		}
+		for _, fork := range cfg.sideChainForks {
+			if fork >= uint64(cfg.chainLength) {
+				return cfg, fmt.Errorf("side chain fork block %d is not below chain length %d", fork, cfg.chainLength)
+			}
+		}
+		if !slices.Contains(cfg.outputs, "sidechains") {
+			cfg.outputs = append(cfg.outputs, "sidechains")
+		}
+	}
 	return cfg, nil
 }
 
@@ -69,8 +118,13 @@ type generator struct {
 	mods      []*modifierInstance
 	modOffset int
 
+	// Blob sidecars of generated transactions.
+	blobs []*blobInfo
+
 	// for write/export
-	blockchain *core.BlockChain
+	blockchain  *core.BlockChain
+	sidechains  []*sideChain
+	outputFiles []string
 }
 
 type modifierInstance struct {
@@ -88,7 +142,7 @@ func newGenerator(cfg generatorConfig) *generator {
 	return &generator{
 		cfg:      cfg,
 		genesis:  genesis,
-		rand:     rand.New(rand.NewSource(10)),
+		rand:     rand.New(rand.NewSource(cfg.seed)),
 		td:       new(big.Int).Set(genesis.Difficulty),
 		virgins:  cfg.createBlockModifiers(),
 		accounts: slices.Clone(knownAccounts),
@@ -129,6 +183,12 @@ func (g *generator) run() error {
 	}
 
 	g.blockchain = bc
+	defer bc.Stop()
+
+	// Create side chains.
+	if err := g.generateSideChains(db, engine, genesis, chain); err != nil {
+		return err
+	}
 	return g.write()
 }
 
@@ -167,6 +227,7 @@ func (g *generator) importChain(engine consensus.Engine, chain []*types.Block) (
 
 func (g *generator) modifyBlock(i int, gen *core.BlockGen) {
 	fmt.Println("generating block", gen.Number())
+	g.setTime(i, gen)
 	if g.genesis.Config.Clique != nil {
 		g.setClique(i, gen)
 	}
@@ -175,6 +236,14 @@ func (g *generator) modifyBlock(i int, gen *core.BlockGen) {
 	g.runModifiers(i, gen)
 }
 
+// setTime applies the configured block time. Note core.GenerateChain always creates
+// blocks 10 seconds apart, so the timestamp has to be adjusted.
+func (g *generator) setTime(i int, gen *core.BlockGen) {
+	if g.cfg.blockTime != blocktimeSec {
+		gen.OffsetTime(int64(g.cfg.blockTime) - blocktimeSec)
+	}
+}
+
 func (g *generator) setClique(i int, gen *core.BlockGen) {
 	mergeblock := g.genesis.Config.MergeNetsplitBlock
 	if mergeblock != nil && gen.Number().Cmp(mergeblock) >= 0 {
@@ -224,7 +293,8 @@ func (g *generator) runModifiers(i int, gen *core.BlockGen) {
 
 	// Modifier scheduling: we cycle through the available modifiers until enough have
 	// executed successfully. It also stops when all of them return false from apply()
-	// because this usually means there is no gas left.
+	// because this usually means there is no gas left. Modifiers with a weight above
+	// one run multiple times per cycle.
 	count := 0
 	refused := 0 // count of consecutive times apply() returned false
 	run := func(mod *modifierInstance) bool {
@@ -243,6 +313,9 @@ func (g *generator) runModifiers(i int, gen *core.BlockGen) {
 	// of unfortunate scheduling, we first try modifiers from g.virgins.
 	for i := 0; i < len(g.virgins) && count < g.cfg.txCount; i++ {
 		mod := g.virgins[i]
+		if g.modifierWeight(mod.name, ctx.NumberU64()) == 0 {
+			continue
+		}
 		if run(mod) {
 			g.mods = append(g.mods, mod)
 			g.virgins = append(g.virgins[:i], g.virgins[i+1:]...)
@@ -251,10 +324,29 @@ func (g *generator) runModifiers(i int, gen *core.BlockGen) {
 	}
 	// If there is any space left, fill it using g.mods.
 	for len(g.mods) > 0 && count < g.cfg.txCount && refused < totalMods {
-		index := g.modOffset % len(g.mods)
-		run(g.mods[index])
+		mod := g.mods[g.modOffset%len(g.mods)]
 		g.modOffset++
+		weight := g.modifierWeight(mod.name, ctx.NumberU64())
+		if weight == 0 {
+			refused++
+			continue
+		}
+		for j := 0; j < weight && count < g.cfg.txCount && run(mod); j++ {
+		}
+	}
+}
+
+// modifierWeight returns the scheduling weight of a modifier in the given block.
+func (g *generator) modifierWeight(name string, num uint64) int {
+	if len(g.cfg.modifiers) == 0 {
+		return 1
+	}
+	for _, r := range g.cfg.modifiers {
+		if r.contains(num) {
+			return r.Weights[name]
+		}
 	}
+	return 0
 }
 
 // instaSeal wraps a consensus engine with instant block sealing. When a block is produced
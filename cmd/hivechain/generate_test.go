@@ -1,8 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 func TestGenerate(t *testing.T) {
@@ -27,3 +38,454 @@ func TestGenerate(t *testing.T) {
 	names, _ := filepath.Glob(filepath.Join(outdir, "*"))
 	t.Log("output files:", names)
 }
+
+func TestGenerateState(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:     1,
+		txCount:        100,
+		chainLength:    10,
+		lastFork:       "shanghai",
+		stateAccounts:  150,
+		stateSlots:     1500,
+		stateContracts: 2,
+		stateBigCode:   2,
+		modifiers: []modifierRange{{Weights: map[string]int{
+			"state-accounts":     10,
+			"state-storage":      1,
+			"state-selfdestruct": 1,
+			"state-bigcode":      1,
+		}}},
+		outputDir: outdir,
+		outputs:   []string{"statestats", "txinfo"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats stateStats
+	if err := readJSON(filepath.Join(outdir, "statestats.json"), &stats); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", stats)
+	if stats.Accounts < cfg.stateAccounts {
+		t.Errorf("too few accounts: %d", stats.Accounts)
+	}
+	if stats.MaxCodeSize != params.MaxCodeSize {
+		t.Errorf("wrong max code size %d", stats.MaxCodeSize)
+	}
+	// Full contracts have an extra slot that tracks the slot count.
+	if stats.MaxStorageSlots != cfg.stateSlots+1 {
+		t.Errorf("wrong max storage slots %d", stats.MaxStorageSlots)
+	}
+	if stats.StorageTries.MaxDepth < 2 {
+		t.Errorf("storage trie depth %d too low", stats.StorageTries.MaxDepth)
+	}
+
+	// Self-destructed contracts must be gone.
+	var txinfo struct {
+		Storage  []stateContractInfo `json:"state-storage"`
+		Destruct []stateContractInfo `json:"state-selfdestruct"`
+	}
+	if err := readJSON(filepath.Join(outdir, "txinfo.json"), &txinfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(txinfo.Storage) != cfg.stateContracts || len(txinfo.Destruct) != cfg.stateContracts {
+		t.Fatalf("wrong number of contracts in txinfo.json: %d storage, %d destruct", len(txinfo.Storage), len(txinfo.Destruct))
+	}
+	state, _ := g.blockchain.State()
+	for _, c := range txinfo.Destruct {
+		if c.DestroyBlock == nil || state.Exist(c.Contract) {
+			t.Errorf("contract %v was not destroyed", c.Contract)
+		}
+	}
+	for _, c := range txinfo.Storage {
+		if c.Slots != cfg.stateSlots {
+			t.Errorf("contract %v has %d slots", c.Contract, c.Slots)
+		}
+		if len(state.GetCode(c.Contract)) == 0 {
+			t.Errorf("contract %v has no code", c.Contract)
+		}
+	}
+}
+
+// This checks that state-selfdestruct stops at Cancun, and reports the contract
+// it was filling at that point.
+func TestGenerateStateCancun(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:     1,
+		txCount:        100,
+		forkInterval:   1,
+		chainLength:    20,
+		lastFork:       "cancun",
+		stateSlots:     100000,
+		stateContracts: 1,
+		modifiers: []modifierRange{{Weights: map[string]int{
+			"state-selfdestruct": 1,
+		}}},
+		outputDir: outdir,
+		outputs:   []string{"txinfo"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var txinfo struct {
+		Destruct []stateContractInfo `json:"state-selfdestruct"`
+	}
+	if err := readJSON(filepath.Join(outdir, "txinfo.json"), &txinfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(txinfo.Destruct) != 1 {
+		t.Fatalf("wrong contracts in txinfo.json: %+v", txinfo.Destruct)
+	}
+	c := txinfo.Destruct[0]
+	if !c.Abandoned || c.DestroyBlock != nil || c.Slots == 0 || c.Slots >= cfg.stateSlots {
+		t.Errorf("contract not reported as abandoned: %+v", c)
+	}
+	state, _ := g.blockchain.State()
+	if len(state.GetCode(c.Contract)) == 0 {
+		t.Errorf("abandoned contract %v has no code", c.Contract)
+	}
+}
+
+func TestGenerateEra1(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:   1,
+		txCount:      10,
+		forkInterval: 2,
+		chainLength:  40,
+		outputDir:    outdir,
+		outputs:      []string{"era1"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(outdir, "*.era1"))
+	if len(files) != 1 {
+		t.Fatalf("wrong era1 files: %v", files)
+	}
+	blocks, root, err := readEra1(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := fmt.Sprintf("hivechain-00000-%x.era1", root[:4]); filepath.Base(files[0]) != name {
+		t.Errorf("wrong file name %s, want %s", filepath.Base(files[0]), name)
+	}
+
+	// The file should contain all pre-merge blocks, starting at genesis.
+	bc := g.blockchain
+	merge, _ := g.mergeBlock()
+	if len(blocks) == 0 || uint64(len(blocks)) > merge+1 {
+		t.Fatalf("wrong number of blocks %d, merge block %d", len(blocks), merge)
+	}
+	if next := bc.GetBlockByNumber(uint64(len(blocks))); next.Difficulty().Sign() != 0 {
+		t.Errorf("pre-merge block %d missing in era1 file", next.Number())
+	}
+	var txs int
+	for i, b := range blocks {
+		want := bc.GetBlockByNumber(uint64(i))
+		if b.Block.Hash() != want.Hash() || types.DeriveSha(types.Transactions(b.Block.Transactions()), trie.NewStackTrie(nil)) != want.TxHash() {
+			t.Fatalf("block %d: mismatch", i)
+		}
+		if b.TD.Cmp(bc.GetTd(want.Hash(), want.NumberU64())) != 0 {
+			t.Errorf("block %d: wrong total difficulty %v", i, b.TD)
+		}
+		if types.DeriveSha(b.Receipts, trie.NewStackTrie(nil)) != want.ReceiptHash() {
+			t.Errorf("block %d: wrong receipts", i)
+		}
+		txs += len(b.Block.Transactions())
+	}
+	if txs == 0 {
+		t.Error("no transactions in era1 file")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:   1,
+		txCount:      10,
+		forkInterval: 2,
+		chainLength:  30,
+		outputDir:    outdir,
+		outputs:      []string{"genesis", "chain"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newGenerator(cfg).run(); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := loadGenesis(filepath.Join(outdir, "genesis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trimdir := t.TempDir()
+	trimcfg := generatorConfig{outputDir: trimdir, outputs: []string{"headfcu", "headblock"}}
+	newchain := filepath.Join(trimdir, "chain.rlp")
+	if err := trimChain(trimcfg, genesis, filepath.Join(outdir, "chain.rlp"), newchain, 5, 20); err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := readChain(newchain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 16 || blocks[0].NumberU64() != 5 || blocks[15].NumberU64() != 20 {
+		t.Fatalf("wrong blocks in trimmed chain: %d blocks", len(blocks))
+	}
+	var head types.Header
+	if err := readJSON(filepath.Join(trimdir, "headblock.json"), &head); err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != blocks[15].Hash() {
+		t.Fatalf("wrong head block %d in headblock.json", head.Number)
+	}
+
+	// Outputs which are not computed from the chain are rejected, as well as the
+	// chain outputs, which would conflict with the trimmed chain file.
+	for _, output := range []string{"accounts", "txinfo", "sidechains", "config", "chain", "powchain"} {
+		cfg := generatorConfig{outputDir: trimdir, outputs: []string{output}}
+		if err := trimChain(cfg, genesis, filepath.Join(outdir, "chain.rlp"), newchain, 5, 20); err == nil {
+			t.Errorf("trim succeeded with output %s", output)
+		}
+	}
+
+	// Trimming against a different genesis block must fail validation.
+	genesis.GasLimit++
+	if err := trimChain(trimcfg, genesis, filepath.Join(outdir, "chain.rlp"), newchain, 1, 0); err == nil {
+		t.Fatal("trim succeeded with wrong genesis")
+	}
+}
+
+func TestGenerateBlobs(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:  1,
+		txCount:     50,
+		chainLength: 30,
+		outputDir:   outdir,
+		outputs:     []string{"blobs"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check that excess blob gas goes up to the limit and back down.
+	var peak, last uint64
+	var reachedHigh, decreased bool
+	for n := uint64(1); n <= uint64(cfg.chainLength); n++ {
+		excess := *g.blockchain.GetBlockByNumber(n).ExcessBlobGas()
+		if excess > peak {
+			peak = excess
+		}
+		if excess >= blobExcessHigh {
+			reachedHigh = true
+		}
+		if reachedHigh && excess < last {
+			decreased = true
+		}
+		last = excess
+	}
+	if !reachedHigh || !decreased {
+		t.Fatalf("excess blob gas did not go up and down (peak %d, last %d)", peak, last)
+	}
+
+	// Check that all blobs in the chain are in blobs.json.
+	var blobs map[common.Hash]blobInfo
+	if err := readJSON(filepath.Join(outdir, "blobs.json"), &blobs); err != nil {
+		t.Fatal(err)
+	}
+	hashes := make(map[common.Hash]bool)
+	for n := uint64(1); n <= uint64(cfg.chainLength); n++ {
+		for _, tx := range g.blockchain.GetBlockByNumber(n).Transactions() {
+			for _, h := range tx.BlobHashes() {
+				b, ok := blobs[h]
+				if !ok {
+					t.Fatalf("blob %v of tx %v missing in blobs.json", h, tx.Hash())
+				}
+				var (
+					blob       kzg4844.Blob
+					commitment kzg4844.Commitment
+					proof      kzg4844.Proof
+				)
+				copy(blob[:], b.Blob)
+				copy(commitment[:], b.Commitment)
+				copy(proof[:], b.Proof)
+				vh := sha256.Sum256(commitment[:])
+				vh[0] = 0x01
+				if common.Hash(vh) != h {
+					t.Fatalf("blob %v has wrong commitment", h)
+				}
+				if err := kzg4844.VerifyBlobProof(blob, commitment, proof); err != nil {
+					t.Fatalf("blob %v has invalid proof: %v", h, err)
+				}
+				hashes[h] = true
+			}
+		}
+	}
+	if len(hashes) != len(blobs) {
+		t.Fatalf("blobs.json has %d blobs, chain has %d", len(blobs), len(hashes))
+	}
+}
+
+func TestGenerateSideChains(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:      1,
+		txCount:         10,
+		chainLength:     20,
+		sideChainForks:  []uint64{5, 10},
+		sideChainLength: 3,
+		outputDir:       outdir,
+		outputs:         []string{"chain"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, fork := range cfg.sideChainForks {
+		name := fmt.Sprintf("sidechain%d", i+1)
+		blocks, err := readChain(filepath.Join(outdir, name+".rlp"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blocks) != cfg.sideChainLength {
+			t.Fatalf("%s: wrong number of blocks %d", name, len(blocks))
+		}
+		if blocks[0].ParentHash() != g.blockchain.GetBlockByNumber(fork).Hash() {
+			t.Fatalf("%s: first block does not fork off canonical block %d", name, fork)
+		}
+		for _, b := range blocks {
+			if b.Hash() == g.blockchain.GetBlockByNumber(b.NumberU64()).Hash() {
+				t.Fatalf("%s: block %d is canonical", name, b.NumberU64())
+			}
+		}
+		var fcu []rpcRequest
+		if err := readJSON(filepath.Join(outdir, name+"-fcu.json"), &fcu); err != nil {
+			t.Fatal(err)
+		}
+		if len(fcu) != len(blocks) {
+			t.Fatalf("%s: wrong number of fcu requests %d", name, len(fcu))
+		}
+	}
+}
+
+// This test checks that the generator output only depends on the configuration.
+func TestGenerateReproducible(t *testing.T) {
+	generate := func(seed int64) string {
+		outdir := t.TempDir()
+		cfg := generatorConfig{
+			txInterval:   1,
+			txCount:      10,
+			forkInterval: 1,
+			chainLength:  25,
+			seed:         seed,
+			outputDir:    outdir,
+			outputs:      []string{"genesis", "chain"},
+		}
+		cfg, err := cfg.withDefaults()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := newGenerator(cfg).run(); err != nil {
+			t.Fatal(err)
+		}
+		return outdir
+	}
+	equalFiles := func(dir1, dir2, file string) bool {
+		content1, err := os.ReadFile(filepath.Join(dir1, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content2, err := os.ReadFile(filepath.Join(dir2, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.Equal(content1, content2)
+	}
+
+	dir1, dir2, dir3 := generate(5), generate(5), generate(6)
+	for _, file := range []string{"genesis.json", "chain.rlp"} {
+		if !equalFiles(dir1, dir2, file) {
+			t.Errorf("%s differs between runs with the same seed", file)
+		}
+	}
+	if equalFiles(dir1, dir3, "chain.rlp") {
+		t.Error("chain.rlp is identical for different seeds")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:      1,
+		txCount:         10,
+		chainLength:     10,
+		seed:            3,
+		sideChainForks:  []uint64{5},
+		sideChainLength: 2,
+		outputDir:       outdir,
+		outputs:         []string{"genesis", "chain", "headfcu", "config"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newGenerator(cfg).run(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := verifyChain(outdir)
+	if err != nil {
+		t.Fatal("verification failed:", err)
+	}
+	if n != 6 {
+		t.Fatalf("wrong number of verified files %d", n)
+	}
+
+	// Modify the chain.
+	if err := os.WriteFile(filepath.Join(outdir, "chain.rlp"), []byte{0xc0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyChain(outdir); err == nil {
+		t.Fatal("verification succeeded for modified chain")
+	}
+}
+
+func readJSON(file string, v any) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -18,9 +18,13 @@ var initialBalance, _ = new(big.Int).SetString("10000000000000000000000000000000
 const (
 	genesisBaseFee = params.InitialBaseFee
 	blocktimeSec   = 10 // hard-coded in core.GenerateChain
+	minBlockTime   = 6  // see forkTimestamp
 	cliqueEpoch    = 30000
+	defaultSeed    = 10
 )
 
+var defaultChainID, _ = new(big.Int).SetString("3503995874084926", 10)
+
 var (
 	cliqueSignerKey  = knownAccounts[8].key
 	cliqueSignerAddr = crypto.PubkeyToAddress(cliqueSignerKey.PublicKey)
@@ -58,13 +62,12 @@ var (
 func (cfg *generatorConfig) createChainConfig() *params.ChainConfig {
 	chaincfg := new(params.ChainConfig)
 
-	chainid, _ := new(big.Int).SetString("3503995874084926", 10)
-	chaincfg.ChainID = chainid
+	chaincfg.ChainID = new(big.Int).Set(cfg.chainID)
 
 	// Set consensus algorithm.
 	if cfg.clique {
 		chaincfg.Clique = &params.CliqueConfig{
-			Period: blocktimeSec,
+			Period: cfg.blockTime,
 			Epoch:  cliqueEpoch,
 		}
 	} else {
@@ -72,9 +75,14 @@ func (cfg *generatorConfig) createChainConfig() *params.ChainConfig {
 	}
 
 	// Apply forks.
-	forks := cfg.forkBlocks()
-	for fork, b := range forks {
-		timestamp := cfg.blockTimestamp(b)
+	for fork, f := range cfg.forkSchedule() {
+		var b, timestamp uint64
+		if f.Block != nil {
+			b = *f.Block
+		}
+		if f.Time != nil {
+			timestamp = *f.Time
+		}
 
 		switch fork {
 		// number-based forks
@@ -146,7 +154,7 @@ func (cfg *generatorConfig) createGenesis() *core.Genesis {
 	} else {
 		g.ExtraData = []byte("hivechain")
 	}
-	g.GasLimit = params.GenesisGasLimit * 8
+	g.GasLimit = cfg.gasLimit
 	zero := new(big.Int)
 	if g.Config.IsLondon(zero) {
 		g.BaseFee = big.NewInt(genesisBaseFee)
@@ -161,6 +169,9 @@ func (cfg *generatorConfig) createGenesis() *core.Genesis {
 	add4788Contract(g.Alloc)
 	addSnapTestContract(g.Alloc)
 	addEmitContract(g.Alloc)
+	for addr, acc := range cfg.alloc {
+		g.Alloc[addr] = acc
+	}
 
 	return &g
 }
@@ -196,6 +207,25 @@ func addEmitContract(ga core.GenesisAlloc) {
 	}
 }
 
+// forkSchedule returns the activation block or timestamp of all enabled forks. If no
+// explicit schedule is configured, it is computed from forkInterval.
+func (cfg *generatorConfig) forkSchedule() map[string]forkSpec {
+	if cfg.forks != nil {
+		return cfg.forks
+	}
+	schedule := make(map[string]forkSpec)
+	for fork, b := range cfg.forkBlocks() {
+		b := b
+		if slices.Contains(timeBasedForkNames, fork) {
+			t := cfg.forkTimestamp(b)
+			schedule[fork] = forkSpec{Time: &t}
+		} else {
+			schedule[fork] = forkSpec{Block: &b}
+		}
+	}
+	return schedule
+}
+
 // forkBlocks computes the block numbers where forks occur. Forks get enabled based on the
 // forkInterval. If the total number of requested blocks (chainLength) is lower than
 // necessary, the remaining forks activate on the last chain block.
@@ -228,7 +258,39 @@ func (cfg *generatorConfig) lastForkIndex() int {
 }
 
 func (cfg *generatorConfig) blockTimestamp(num uint64) uint64 {
-	return num * blocktimeSec
+	return num * cfg.blockTime
+}
+
+// forkTimestamp returns the activation time of a time-based fork that should activate at
+// the given block number.
+//
+// Headers created by core.GenerateChain initially have a timestamp of parent+10, and the
+// block time is applied afterwards. Since some header fields are chosen based on the
+// initial timestamp, the fork must be active at both the initial and final timestamp of
+// the block, and inactive at both timestamps of its parent. With a block time below
+// minBlockTime, no such timestamp exists.
+func (cfg *generatorConfig) forkTimestamp(num uint64) uint64 {
+	if num == 0 {
+		return 0
+	}
+	t := cfg.blockTimestamp(num)
+	if initial := cfg.blockTimestamp(num-1) + blocktimeSec; initial < t {
+		t = initial
+	}
+	return t
+}
+
+// checkForkTimestamp verifies that a time-based fork activates consistently with the
+// configured block time. See forkTimestamp.
+func (cfg *generatorConfig) checkForkTimestamp(t uint64) bool {
+	if t == 0 {
+		return true
+	}
+	num := (t + cfg.blockTime - 1) / cfg.blockTime // first block past the fork
+	if cfg.blockTimestamp(num-1)+blocktimeSec < t {
+		return false
+	}
+	return num < 2 || cfg.blockTimestamp(num-2)+blocktimeSec < t
 }
 
 // cliqueInit creates the genesis extradata for a clique network with one signer.
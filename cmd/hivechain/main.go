@@ -2,9 +2,13 @@
 //
 // The 'generate' subcommand mines a new chain:
 //
-//	hivechain generate -length 10 -genesis ./genesis.json -blocktime 30 -output .
+//	hivechain generate -length 10 -outdir .
 //
-// The 'print' subcommand displays blocks in a chain.rlp file:
+// The chain can also be described by a spec file:
+//
+//	hivechain generate -spec chain.yaml -outdir .
+//
+// The 'print' subcommand displays blocks in a chain.rlp or era1 file:
 //
 //	hivechain print -v chain.rlp
 //
@@ -12,9 +16,19 @@
 //
 //	hivechain print-genesis genesis.json
 //
-// The 'trim' subcommand extracts a range of blocks from a chain.rlp file:
+// The 'trim' subcommand extracts a range of blocks from a chain.rlp file. The chain is
+// validated against the genesis block before writing the output:
+//
+//	hivechain trim -genesis genesis.json -from 10 -to 100 chain.rlp newchain.rlp
+//
+// The 'verify' subcommand re-generates a chain from the config.json output file and
+// checks that the output files are identical:
 //
-//	hivechain trim -from 10 -to 100 chain.rlp newchain.rlp
+//	hivechain verify ./chain
+//
+// The 'rpctests' subcommand creates rpc-compat test files for a generated chain:
+//
+//	hivechain rpctests -outdir ./tests ./chain
 package main
 
 import (
@@ -24,6 +38,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -48,6 +64,14 @@ func main() {
 		generateCommand(os.Args[2:])
 	case "print":
 		printCommand(os.Args[2:])
+	case "print-genesis":
+		printGenesisCommand(os.Args[2:])
+	case "trim":
+		trimCommand(os.Args[2:])
+	case "verify":
+		verifyCommand(os.Args[2:])
+	case "rpctests":
+		rpcTestsCommand(os.Args[2:])
 	default:
 		flag.Usage()
 		os.Exit(1)
@@ -57,8 +81,10 @@ func main() {
 // generateCommand generates a test chain.
 func generateCommand(args []string) {
 	var (
-		cfg     generatorConfig
-		outlist = flag.String("outputs", "", "Enabled output modules")
+		cfg       generatorConfig
+		outlist   = flag.String("outputs", "", "Enabled output modules")
+		sideForks = flag.String("sidechains", "", "Comma-separated block numbers where side chains fork off")
+		specFile  = flag.String("spec", "", "Chain spec file (YAML or JSON)")
 	)
 	flag.IntVar(&cfg.chainLength, "length", 2, "The length of the pow chain to generate")
 	flag.IntVar(&cfg.txInterval, "tx-interval", 10, "Add transactions to chain every n blocks")
@@ -67,6 +93,12 @@ func generateCommand(args []string) {
 	flag.StringVar(&cfg.outputDir, "outdir", ".", "Destination directory")
 	flag.StringVar(&cfg.lastFork, "lastfork", "", "Name of the last fork to activate")
 	flag.BoolVar(&cfg.clique, "clique", false, "Create a clique chain")
+	flag.IntVar(&cfg.sideChainLength, "sidechain-length", 1, "The number of blocks in each side chain")
+	flag.Int64Var(&cfg.seed, "seed", defaultSeed, "Random seed of the generator")
+	flag.IntVar(&cfg.stateAccounts, "state-accounts", 0, "Number of accounts created by the state-accounts modifier")
+	flag.IntVar(&cfg.stateSlots, "state-slots", 0, "Number of storage slots per contract of the state-storage and state-selfdestruct modifiers")
+	flag.IntVar(&cfg.stateContracts, "state-contracts", 0, "Number of contracts filled by each of the state-storage and state-selfdestruct modifiers")
+	flag.IntVar(&cfg.stateBigCode, "state-bigcode", 0, "Number of max-size contracts created by the state-bigcode modifier")
 	flag.CommandLine.Parse(args)
 
 	if *outlist != "" {
@@ -76,9 +108,26 @@ func generateCommand(args []string) {
 		cfg.outputs = splitAndTrim(*outlist)
 	}
 
+	if *specFile != "" {
+		spec, err := loadChainSpec(*specFile)
+		if err != nil {
+			fatal(err)
+		}
+		if err := spec.apply(&cfg); err != nil {
+			fatal(fmt.Errorf("%s: %v", *specFile, err))
+		}
+	}
+	for _, s := range splitAndTrim(*sideForks) {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			fatalf("invalid -sidechains block number %q", s)
+		}
+		cfg.sideChainForks = append(cfg.sideChainForks, n)
+	}
+
 	cfg, err := cfg.withDefaults()
 	if err != nil {
-		panic(err)
+		fatal(err)
 	}
 	g := newGenerator(cfg)
 	if err := g.run(); err != nil {
@@ -88,7 +137,7 @@ func generateCommand(args []string) {
 
 func usage() {
 	o := flag.CommandLine.Output()
-	fmt.Fprintln(o, "Usage: hivechain generate|print [options...]")
+	fmt.Fprintln(o, "Usage: hivechain generate|print|print-genesis|trim|verify|rpctests [options...]")
 	flag.PrintDefaults()
 	fmt.Fprintln(o, "")
 	fmt.Fprintln(o, "List of available -outputs:")
@@ -104,7 +153,11 @@ func printCommand(args []string) {
 	)
 	flag.CommandLine.Parse(args)
 	if flag.NArg() != 1 {
-		fatalf("Usage: hivechain print [ options ] <chain.rlp>")
+		fatalf("Usage: hivechain print [ options ] <chain.rlp|file.era1>")
+	}
+	if strings.EqualFold(filepath.Ext(flag.Arg(0)), ".era1") {
+		printEra1(flag.Arg(0), *verbose)
+		return
 	}
 
 	file, err := os.Open(flag.Arg(0))
@@ -131,6 +184,36 @@ func printCommand(args []string) {
 	}
 }
 
+// printEra1 displays the blocks of an era1 file.
+func printEra1(file string, verbose bool) {
+	blocks, root, err := readEra1(file)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("accumulator root %x\n", root)
+	for i, b := range blocks {
+		if verbose {
+			js, _ := json.MarshalIndent(b.Block.Header(), "", "  ")
+			fmt.Printf("%d: %s\n", i, js)
+		}
+		fmt.Printf("%d: number %d, %x, td %v, %d receipts\n", i, b.Block.Number(), b.Block.Hash(), b.TD, len(b.Receipts))
+	}
+}
+
+// printGenesisCommand displays the block header of a genesis.json file.
+func printGenesisCommand(args []string) {
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 1 {
+		fatalf("Usage: hivechain print-genesis <genesis.json>")
+	}
+	genesis, err := loadGenesis(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	js, _ := json.MarshalIndent(genesis.ToBlock().Header(), "", "  ")
+	fmt.Printf("%s\n", js)
+}
+
 func splitAndTrim(s string) []string {
 	var list []string
 	for _, s := range strings.Split(s, ",") {
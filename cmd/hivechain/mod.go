@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -24,10 +25,11 @@ func register(name string, new func() blockModifier) {
 }
 
 type genBlockContext struct {
-	index   int
-	block   *core.BlockGen
-	gen     *generator
-	txcount int
+	index     int
+	block     *core.BlockGen
+	gen       *generator
+	txcount   int
+	blobcount int
 }
 
 // Number returns the block number.
@@ -56,6 +58,10 @@ func (ctx *genBlockContext) AddNewTx(sender *genAccount, data types.TxData) *typ
 	if err != nil {
 		panic(err)
 	}
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil {
+		ctx.gen.addBlobSidecar(ctx.NumberU64(), tx.Hash(), sidecar)
+		ctx.blobcount += len(sidecar.Blobs)
+	}
 	ctx.block.AddTx(tx.WithoutBlobTxSidecar())
 	ctx.txcount++
 	return tx
@@ -89,6 +95,26 @@ func (ctx *genBlockContext) TxGasFeeCap() *big.Int {
 	return fee.Add(fee, ctx.block.BaseFee())
 }
 
+// ExcessBlobGas returns the excess blob gas of the current block.
+func (ctx *genBlockContext) ExcessBlobGas() uint64 {
+	parent := ctx.ParentBlock()
+	if parent.ExcessBlobGas() == nil {
+		return 0
+	}
+	return eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas(), *parent.BlobGasUsed())
+}
+
+// TxBlobFeeCap returns the blob gasprice that should be used for blob transactions.
+func (ctx *genBlockContext) TxBlobFeeCap() *big.Int {
+	fee := eip4844.CalcBlobFee(ctx.ExcessBlobGas())
+	return fee.Mul(fee, big.NewInt(2))
+}
+
+// BlobsLeft returns the number of blobs that can still be added to the block.
+func (ctx *genBlockContext) BlobsLeft() int {
+	return maxBlobsPerBlock - ctx.blobcount
+}
+
 // AccountNonce returns the current nonce of an address.
 func (ctx *genBlockContext) AccountNonce(addr common.Address) uint64 {
 	return ctx.block.TxNonce(addr)
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+const (
+	maxBlobsPerBlock    = params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+	targetBlobsPerBlock = params.BlobTxTargetBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+
+	// blobExcessHigh is the excess blob gas at which modBlobs stops
+	// creating blocks above the blob target.
+	blobExcessHigh = 4 * params.BlobTxTargetBlobGasPerBlock
+
+	// blobFieldElements is the number of field elements filled with data in
+	// generated blobs. The remaining elements are zero.
+	blobFieldElements = 64
+)
+
+func init() {
+	register("tx-blobs", func() blockModifier {
+		return &modBlobs{rising: true}
+	})
+}
+
+// modBlobs creates blob transactions with a varying number of blobs.
+//
+// The modifier alternates between two phases. While rising, it fills blocks above the blob
+// target until the excess blob gas reaches blobExcessHigh. It then switches to adding
+// fewer blobs than the target until the excess blob gas is back at zero. Note the excess
+// blob gas only rises if the modifier runs in most blocks, i.e. when -tx-count is larger
+// than the number of modifiers.
+type modBlobs struct {
+	rising bool
+
+	txs []blobTxInfo
+}
+
+type blobTxInfo struct {
+	TxHash        common.Hash    `json:"txhash"`
+	Sender        common.Address `json:"sender"`
+	Block         hexutil.Uint64 `json:"block"`
+	Index         int            `json:"indexInBlock"`
+	BlobHashes    []common.Hash  `json:"blobHashes"`
+	ExcessBlobGas hexutil.Uint64 `json:"excessBlobGas"`
+}
+
+func (m *modBlobs) apply(ctx *genBlockContext) bool {
+	if !ctx.ChainConfig().IsCancun(ctx.Number(), ctx.Timestamp()) || !ctx.HasGas(params.TxGas) {
+		return false
+	}
+
+	excess := ctx.ExcessBlobGas()
+	switch {
+	case m.rising && excess >= blobExcessHigh:
+		m.rising = false
+	case !m.rising && excess == 0:
+		m.rising = true
+	}
+
+	// The number of blobs in the block depends on the phase and the block number.
+	var blockBlobs int
+	if m.rising {
+		blockBlobs = targetBlobsPerBlock + 1 + int(ctx.NumberU64()%(maxBlobsPerBlock-targetBlobsPerBlock))
+	} else {
+		blockBlobs = int(ctx.NumberU64() % targetBlobsPerBlock)
+	}
+	avail := blockBlobs - (maxBlobsPerBlock - ctx.BlobsLeft())
+	if avail <= 0 {
+		return false
+	}
+	count := 1 + int(ctx.TxRandomValue()%uint64(avail))
+
+	sender := ctx.TxSenderAccount()
+	sidecar := m.makeSidecar(ctx, count)
+	txdata := &types.BlobTx{
+		Nonce:      ctx.AccountNonce(sender.addr),
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.MustFromBig(ctx.TxGasFeeCap()),
+		Gas:        params.TxGas,
+		To:         pickRecipient(ctx),
+		Value:      uint256.NewInt(1),
+		BlobFeeCap: uint256.MustFromBig(ctx.TxBlobFeeCap()),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	}
+
+	txindex := ctx.TxCount()
+	tx := ctx.AddNewTx(sender, txdata)
+	m.txs = append(m.txs, blobTxInfo{
+		TxHash:        tx.Hash(),
+		Sender:        sender.addr,
+		Block:         hexutil.Uint64(ctx.NumberU64()),
+		Index:         txindex,
+		BlobHashes:    tx.BlobHashes(),
+		ExcessBlobGas: hexutil.Uint64(excess),
+	})
+	return true
+}
+
+func (m *modBlobs) txInfo() any {
+	return m.txs
+}
+
+// makeSidecar creates a sidecar containing count blobs with random content.
+func (m *modBlobs) makeSidecar(ctx *genBlockContext, count int) *types.BlobTxSidecar {
+	rng := rand.New(rand.NewSource(int64(ctx.TxRandomValue())))
+	sidecar := new(types.BlobTxSidecar)
+	for i := 0; i < count; i++ {
+		var blob kzg4844.Blob
+		for j := 0; j < blobFieldElements; j++ {
+			// The first byte of each field element is left zero to keep
+			// the element below the BLS modulus.
+			binary.BigEndian.PutUint64(blob[j*32+24:], rng.Uint64())
+		}
+		commitment, err := kzg4844.BlobToCommitment(blob)
+		if err != nil {
+			panic(err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+		if err != nil {
+			panic(err)
+		}
+		sidecar.Blobs = append(sidecar.Blobs, blob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar
+}
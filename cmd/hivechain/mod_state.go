@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// The modifiers in this file create large amounts of state for snap sync tests. They
+// only run when a target state size is configured, see the -state-* flags.
+
+func init() {
+	register("state-accounts", func() blockModifier {
+		return &modStateAccounts{}
+	})
+	register("state-storage", func() blockModifier {
+		return &modStateStorage{}
+	})
+	register("state-selfdestruct", func() blockModifier {
+		return &modStateStorage{destroy: true}
+	})
+	register("state-bigcode", func() blockModifier {
+		return &modStateBigCode{}
+	})
+}
+
+// modStateAccounts creates new accounts by sending value to fresh addresses.
+type modStateAccounts struct {
+	created int
+}
+
+func (m *modStateAccounts) apply(ctx *genBlockContext) bool {
+	if m.created >= ctx.gen.cfg.stateAccounts || !ctx.HasGas(params.TxGas) {
+		return false
+	}
+
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], uint64(m.created))
+	recipient := common.BytesToAddress(crypto.Keccak256([]byte("state-accounts"), index[:]))
+	sender := ctx.TxSenderAccount()
+	ctx.AddNewTx(sender, &types.LegacyTx{
+		Nonce:    ctx.AccountNonce(sender.addr),
+		Gas:      params.TxGas,
+		GasPrice: ctx.TxGasFeeCap(),
+		To:       &recipient,
+		Value:    big.NewInt(int64(m.created + 1)),
+	})
+	m.created++
+	return true
+}
+
+func (m *modStateAccounts) txInfo() any {
+	return nil
+}
+
+const (
+	fillSlotGas    = 22200 // gas per storage slot created by fillstorage
+	fillTxGas      = 50000 // gas of a fillstorage call, excluding the slots
+	maxSlotsPerTx  = 1000  // limit for storage slots created by a single transaction
+	deployExtraGas = 15000 // extra gas for constructor execution
+)
+
+// modStateStorage deploys contracts and fills their storage until they have the
+// configured number of slots, and stops when the configured number of contracts is full.
+// When destroy is set, full contracts are self-destructed, which wipes their storage.
+// Since EIP-6780, SELFDESTRUCT no longer deletes existing contracts, so the destroy mode
+// stops when Cancun is activated. The contract being filled at that point is abandoned.
+type modStateStorage struct {
+	destroy bool
+
+	current   *stateContractInfo
+	contracts []stateContractInfo
+}
+
+type stateContractInfo struct {
+	Contract     common.Address  `json:"contract"`
+	Slots        int             `json:"slots"`
+	Block        hexutil.Uint64  `json:"block"`
+	DestroyBlock *hexutil.Uint64 `json:"destroyBlock,omitempty"`
+	Abandoned    bool            `json:"abandoned,omitempty"`
+}
+
+func (m *modStateStorage) apply(ctx *genBlockContext) bool {
+	target := ctx.gen.cfg.stateSlots
+	switch {
+	case target == 0 || len(m.contracts) >= ctx.gen.cfg.stateContracts:
+		return false
+	case m.destroy && ctx.ChainConfig().IsCancun(ctx.Number(), ctx.Timestamp()):
+		m.abandon()
+		return false
+	case m.current == nil:
+		return m.deploy(ctx)
+	case m.current.Slots < target:
+		return m.fill(ctx, target)
+	default:
+		return m.finish(ctx)
+	}
+}
+
+func (m *modStateStorage) deploy(ctx *genBlockContext) bool {
+	var code []byte
+	code = append(code, deployerCode...)
+	code = append(code, fillstorageCode...)
+	gas := ctx.TxCreateIntrinsicGas(code)
+	gas += uint64(len(fillstorageCode)) * params.CreateDataGas
+	gas += deployExtraGas
+	if !ctx.HasGas(gas) {
+		return false
+	}
+
+	sender := ctx.TxSenderAccount()
+	nonce := ctx.AccountNonce(sender.addr)
+	ctx.AddNewTx(sender, &types.LegacyTx{
+		Nonce:    nonce,
+		Gas:      gas,
+		GasPrice: ctx.TxGasFeeCap(),
+		Data:     code,
+	})
+	m.current = &stateContractInfo{
+		Contract: crypto.CreateAddress(sender.addr, nonce),
+		Block:    hexutil.Uint64(ctx.NumberU64()),
+	}
+	return true
+}
+
+func (m *modStateStorage) fill(ctx *genBlockContext, target int) bool {
+	if !ctx.HasGas(fillTxGas + fillSlotGas) {
+		return false
+	}
+	count := target - m.current.Slots
+	if count > maxSlotsPerTx {
+		count = maxSlotsPerTx
+	}
+	if n := int((ctx.block.Gas() - fillTxGas) / fillSlotGas); count > n {
+		count = n
+	}
+
+	gas := fillTxGas + uint64(count)*fillSlotGas
+	sender := ctx.TxSenderAccount()
+	ctx.AddNewTx(sender, &types.LegacyTx{
+		Nonce:    ctx.AccountNonce(sender.addr),
+		Gas:      gas,
+		GasPrice: ctx.TxGasFeeCap(),
+		To:       &m.current.Contract,
+		Data:     common.BigToHash(big.NewInt(int64(count))).Bytes(),
+	})
+	m.current.Slots += count
+	return true
+}
+
+// finish stores the full contract. In destroy mode, the contract is self-destructed first.
+func (m *modStateStorage) finish(ctx *genBlockContext) bool {
+	if m.destroy {
+		if !ctx.HasGas(fillTxGas) {
+			return false
+		}
+		sender := ctx.TxSenderAccount()
+		ctx.AddNewTx(sender, &types.LegacyTx{
+			Nonce:    ctx.AccountNonce(sender.addr),
+			Gas:      fillTxGas,
+			GasPrice: ctx.TxGasFeeCap(),
+			To:       &m.current.Contract,
+		})
+		num := hexutil.Uint64(ctx.NumberU64())
+		m.current.DestroyBlock = &num
+	}
+	m.contracts = append(m.contracts, *m.current)
+	m.current = nil
+	if !m.destroy && len(m.contracts) < ctx.gen.cfg.stateContracts {
+		return m.deploy(ctx)
+	}
+	return true
+}
+
+// abandon stops filling the current contract. It is reported with the slots it has.
+func (m *modStateStorage) abandon() {
+	if m.current != nil {
+		m.current.Abandoned = true
+		m.contracts = append(m.contracts, *m.current)
+		m.current = nil
+	}
+}
+
+// txInfo reports all contracts. A contract which is still being filled when the chain
+// ends is included too, and has fewer slots than configured.
+func (m *modStateStorage) txInfo() any {
+	if m.current != nil {
+		return append(m.contracts, *m.current)
+	}
+	return m.contracts
+}
+
+// modStateBigCode deploys contracts with code of the maximum allowed size.
+type modStateBigCode struct {
+	contracts []deployTxInfo
+}
+
+func (m *modStateBigCode) apply(ctx *genBlockContext) bool {
+	if len(m.contracts) >= ctx.gen.cfg.stateBigCode {
+		return false
+	}
+
+	// The code is random, but starts with STOP to make calls to the contract
+	// succeed. This also avoids the 0xEF prefix forbidden by EIP-3541.
+	bigcode := make([]byte, params.MaxCodeSize)
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], ctx.TxRandomValue())
+	for i := 0; i < len(bigcode); i += sha256.Size {
+		var counter [8]byte
+		binary.BigEndian.PutUint64(counter[:], uint64(i))
+		h := sha256.Sum256(append(seed[:], counter[:]...))
+		copy(bigcode[i:], h[:])
+	}
+	bigcode[0] = 0x00
+
+	var code []byte
+	code = append(code, deployerCode...)
+	code = append(code, bigcode...)
+	gas := ctx.TxCreateIntrinsicGas(code)
+	gas += uint64(len(bigcode)) * params.CreateDataGas
+	gas += deployExtraGas
+	if !ctx.HasGas(gas) {
+		return false
+	}
+
+	sender := ctx.TxSenderAccount()
+	nonce := ctx.AccountNonce(sender.addr)
+	ctx.AddNewTx(sender, &types.LegacyTx{
+		Nonce:    nonce,
+		Gas:      gas,
+		GasPrice: ctx.TxGasFeeCap(),
+		Data:     code,
+	})
+	m.contracts = append(m.contracts, deployTxInfo{
+		Contract: crypto.CreateAddress(sender.addr, nonce),
+		Block:    hexutil.Uint64(ctx.NumberU64()),
+	})
+	return true
+}
+
+func (m *modStateBigCode) txInfo() any {
+	return m.contracts
+}
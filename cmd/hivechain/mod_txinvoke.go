@@ -123,6 +123,11 @@ func (m *modInvokeEmit) apply(ctx *genBlockContext) bool {
 		if !ctx.ChainConfig().IsCancun(ctx.Number(), ctx.Timestamp()) {
 			return false
 		}
+		// Only add a blob if the block has none yet. The blob count of blocks is
+		// controlled by tx-blobs.
+		if ctx.BlobsLeft() < maxBlobsPerBlock {
+			return false
+		}
 		var (
 			blob1     = kzg4844.Blob{0x01}
 			blob1C, _ = kzg4844.BlobToCommitment(blob1)
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 var outputFunctions = map[string]func(*generator) error{
@@ -20,14 +21,19 @@ var outputFunctions = map[string]func(*generator) error{
 	"forkenv":        (*generator).writeForkEnv,
 	"chain":          (*generator).writeChain,
 	"powchain":       (*generator).writePoWChain,
+	"era1":           (*generator).writeEra1,
 	"headstate":      (*generator).writeState,
+	"statestats":     (*generator).writeStateStats,
 	"headblock":      (*generator).writeHeadBlock,
 	"accounts":       (*generator).writeAccounts,
 	"txinfo":         (*generator).writeTxInfo,
+	"blobs":          (*generator).writeBlobs,
 	"fcu":            (*generator).writeEngineFcU,
 	"newpayload":     (*generator).writeEngineNewPayload,
 	"headfcu":        (*generator).writeEngineHeadFcU,
 	"headnewpayload": (*generator).writeEngineHeadNewPayload,
+	"config":         (*generator).writeConfig,
+	"sidechains":     (*generator).writeSideChains,
 }
 
 func outputFunctionNames() []string {
@@ -45,8 +51,16 @@ func (g *generator) write() error {
 		if f == nil {
 			return fmt.Errorf("unknown output %q", name)
 		}
+		if name == "config" {
+			continue // written last, see below
+		}
 		wf = append(wf, f)
 	}
+	// The config output records the hashes of all other output files,
+	// so it has to run after them.
+	if slices.Contains(g.cfg.outputs, "config") {
+		wf = append(wf, (*generator).writeConfig)
+	}
 	for _, f := range wf {
 		if err := f(g); err != nil {
 			return err
@@ -57,6 +71,9 @@ func (g *generator) write() error {
 
 func (g *generator) openOutputFile(file string) (*os.File, error) {
 	path := filepath.Join(g.cfg.outputDir, file)
+	if !slices.Contains(g.outputFiles, file) {
+		g.outputFiles = append(g.outputFiles, file)
+	}
 	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 }
 
@@ -110,8 +127,7 @@ func (g *generator) writeHeadBlock() error {
 
 // writeChain writes all RLP blocks to a file.
 func (g *generator) writeChain() error {
-	path := filepath.Join(g.cfg.outputDir, "chain.rlp")
-	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	out, err := g.openOutputFile("chain.rlp")
 	if err != nil {
 		return err
 	}
@@ -122,8 +138,7 @@ func (g *generator) writeChain() error {
 
 // writePoWChain writes pre-merge RLP blocks to a file.
 func (g *generator) writePoWChain() error {
-	path := filepath.Join(g.cfg.outputDir, "powchain.rlp")
-	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	out, err := g.openOutputFile("powchain.rlp")
 	if err != nil {
 		return err
 	}
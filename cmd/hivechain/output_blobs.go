@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blobInfo is an entry of blobs.json.
+type blobInfo struct {
+	Block      hexutil.Uint64 `json:"block"`
+	TxHash     common.Hash    `json:"txhash"`
+	Index      int            `json:"indexInTx"`
+	Blob       hexutil.Bytes  `json:"blob"`
+	Commitment hexutil.Bytes  `json:"commitment"`
+	Proof      hexutil.Bytes  `json:"proof"`
+
+	hash common.Hash
+}
+
+// addBlobSidecar stores the blobs of a transaction for the blobs output.
+func (g *generator) addBlobSidecar(block uint64, txhash common.Hash, sidecar *types.BlobTxSidecar) {
+	hashes := sidecar.BlobHashes()
+	for i := range sidecar.Blobs {
+		g.blobs = append(g.blobs, &blobInfo{
+			Block:      hexutil.Uint64(block),
+			TxHash:     txhash,
+			Index:      i,
+			Blob:       sidecar.Blobs[i][:],
+			Commitment: sidecar.Commitments[i][:],
+			Proof:      sidecar.Proofs[i][:],
+			hash:       hashes[i],
+		})
+	}
+}
+
+// writeBlobs writes the blob sidecars of all blob transactions, keyed by versioned hash.
+func (g *generator) writeBlobs() error {
+	m := make(map[common.Hash]*blobInfo, len(g.blobs))
+	for _, b := range g.blobs {
+		m[b.hash] = b
+	}
+	return g.writeJSON("blobs.json", &m)
+}
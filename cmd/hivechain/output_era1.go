@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// writeEra1 writes the pre-merge blocks, starting at genesis, as era1 files. Each file
+// contains up to 8192 blocks and is named hivechain-<epoch>-<root>.era1, where root
+// is the first four bytes of the accumulator root.
+func (g *generator) writeEra1() error {
+	head := g.blockchain.CurrentBlock().Number.Uint64()
+	for epoch := uint64(0); epoch*era1MaxSize <= head; epoch++ {
+		var (
+			buf     bytes.Buffer
+			builder = newEra1Builder(&buf)
+			first   = epoch * era1MaxSize
+		)
+		for n := first; n < first+era1MaxSize && n <= head; n++ {
+			block := g.blockchain.GetBlockByNumber(n)
+			if block.Difficulty().Sign() == 0 {
+				break // post-merge
+			}
+			receipts := g.blockchain.GetReceiptsByHash(block.Hash())
+			td := g.blockchain.GetTd(block.Hash(), n)
+			if err := builder.add(block, receipts, td); err != nil {
+				return err
+			}
+		}
+		if len(builder.offsets) == 0 {
+			break
+		}
+		root, err := builder.finish()
+		if err != nil {
+			return err
+		}
+
+		// The file name depends on the content, so the file is created at the end.
+		out, err := g.openOutputFile(fmt.Sprintf("hivechain-%05d-%x.era1", epoch, root[:4]))
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(buf.Bytes())
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
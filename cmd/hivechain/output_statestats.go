@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// stateStats summarizes the head state.
+type stateStats struct {
+	Root        common.Hash `json:"stateRoot"`
+	Accounts    int         `json:"accounts"`
+	Contracts   int         `json:"contracts"`
+	CodeSize    int         `json:"codeSize"`    // total size of unique code
+	MaxCodeSize int         `json:"maxCodeSize"` // size of the largest contract code
+
+	StorageAccounts int `json:"storageAccounts"` // accounts with non-empty storage
+	StorageSlots    int `json:"storageSlots"`
+	MaxStorageSlots int `json:"maxStorageSlots"` // slots of the largest storage trie
+
+	AccountTrie  trieStats `json:"accountTrie"`
+	StorageTries trieStats `json:"storageTries"` // combined stats of all storage tries
+}
+
+// trieStats counts the nodes of a trie. Depth is the number of hashed nodes on the path
+// from the root to a leaf. Nodes embedded into their parent are not counted.
+type trieStats struct {
+	Nodes    int `json:"nodes"`
+	Leaves   int `json:"leaves"`
+	MaxDepth int `json:"maxDepth"`
+}
+
+func (s *trieStats) add(other trieStats) {
+	s.Nodes += other.Nodes
+	s.Leaves += other.Leaves
+	if other.MaxDepth > s.MaxDepth {
+		s.MaxDepth = other.MaxDepth
+	}
+}
+
+// writeStateStats writes statistics about the state tries of the head block.
+func (g *generator) writeStateStats() error {
+	root := g.blockchain.CurrentBlock().Root
+	triedb := g.blockchain.StateCache().TrieDB()
+	stats := stateStats{Root: root}
+
+	codes := make(map[common.Hash]bool)
+	accountTrie, err := trie.New(trie.StateTrieID(root), triedb)
+	if err != nil {
+		return err
+	}
+	stats.AccountTrie, err = iterateTrie(accountTrie, func(key, value []byte) error {
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(value, &acc); err != nil {
+			return fmt.Errorf("invalid account %x: %v", key, err)
+		}
+		stats.Accounts++
+
+		codeHash := common.BytesToHash(acc.CodeHash)
+		if codeHash != types.EmptyCodeHash {
+			stats.Contracts++
+			code, err := g.blockchain.ContractCodeWithPrefix(codeHash)
+			if err != nil {
+				return fmt.Errorf("missing code of account %x: %v", key, err)
+			}
+			if !codes[codeHash] {
+				codes[codeHash] = true
+				stats.CodeSize += len(code)
+			}
+			if len(code) > stats.MaxCodeSize {
+				stats.MaxCodeSize = len(code)
+			}
+		}
+
+		if acc.Root != types.EmptyRootHash {
+			id := trie.StorageTrieID(root, common.BytesToHash(key), acc.Root)
+			storageTrie, err := trie.New(id, triedb)
+			if err != nil {
+				return err
+			}
+			st, err := iterateTrie(storageTrie, func(key, value []byte) error { return nil })
+			if err != nil {
+				return err
+			}
+			stats.StorageAccounts++
+			stats.StorageSlots += st.Leaves
+			if st.Leaves > stats.MaxStorageSlots {
+				stats.MaxStorageSlots = st.Leaves
+			}
+			stats.StorageTries.add(st)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return g.writeJSON("statestats.json", &stats)
+}
+
+// iterateTrie walks all nodes of a trie and calls fn for each leaf.
+func iterateTrie(t *trie.Trie, fn func(key, value []byte) error) (trieStats, error) {
+	var (
+		stats trieStats
+		stack [][]byte // paths of the hashed nodes above the current node
+	)
+	it, err := t.NodeIterator(nil)
+	if err != nil {
+		return stats, err
+	}
+	for it.Next(true) {
+		if it.Hash() != (common.Hash{}) {
+			path := it.Path()
+			for len(stack) > 0 && !bytes.HasPrefix(path, stack[len(stack)-1]) {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, common.CopyBytes(path))
+			stats.Nodes++
+		}
+		if it.Leaf() {
+			stats.Leaves++
+			if len(stack) > stats.MaxDepth {
+				stats.MaxDepth = len(stack)
+			}
+			if err := fn(it.LeafKey(), it.LeafBlob()); err != nil {
+				return stats, err
+			}
+		}
+	}
+	return stats, it.Error()
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rpctests.yaml
+var defaultRPCTestTemplates []byte
+
+// rpcTestTemplate describes an rpc-compat test case.
+type rpcTestTemplate struct {
+	Name    string `yaml:"name"`
+	Method  string `yaml:"method"`
+	Comment string `yaml:"comment"`
+	Params  string `yaml:"params"` // JSON array, processed by text/template
+}
+
+// rpcTestsCommand creates rpc-compat test files for a chain.
+func rpcTestsCommand(args []string) {
+	var (
+		templateFile = flag.String("templates", "", "Test template file (default: built-in templates)")
+		outdir       = flag.String("outdir", ".", "Destination directory of test files")
+	)
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 1 {
+		fatalf("Usage: hivechain rpctests [ options ] <chaindir>")
+	}
+
+	templates := defaultRPCTestTemplates
+	if *templateFile != "" {
+		var err error
+		if templates, err = os.ReadFile(*templateFile); err != nil {
+			fatal(err)
+		}
+	}
+	var list []rpcTestTemplate
+	if err := yaml.Unmarshal(templates, &list); err != nil {
+		fatalf("invalid test templates: %v", err)
+	}
+	if err := writeRPCTests(flag.Arg(0), *outdir, list); err != nil {
+		fatal(err)
+	}
+}
+
+// writeRPCTests serves the chain in chaindir over RPC in chaindir, sends the requests defined by
+// the templates and writes the exchanges to .io files in outdir.
+//
+// The chain directory must contain genesis.json and chain.rlp. If it also contains
+// txinfo.json, transactions from the file can be used in templates.
+func writeRPCTests(chaindir, outdir string, templates []rpcTestTemplate) error {
+	genesis, err := loadGenesis(filepath.Join(chaindir, "genesis.json"))
+	if err != nil {
+		return err
+	}
+	blocks, err := readChain(filepath.Join(chaindir, "chain.rlp"))
+	if err != nil {
+		return err
+	}
+	txinfo, err := loadTxInfo(filepath.Join(chaindir, "txinfo.json"))
+	if err != nil {
+		return err
+	}
+
+	n, err := startRPCTestNode(genesis, blocks)
+	if err != nil {
+		return err
+	}
+	defer n.close()
+
+	funcs := n.templateFuncs(txinfo)
+	for _, tt := range templates {
+		if tt.Name == "" || tt.Method == "" {
+			return errors.New("test template without name or method")
+		}
+		params, err := renderParams(tt, funcs)
+		if err != nil {
+			fmt.Printf("skipping %s/%s: %v\n", tt.Method, tt.Name, err)
+			continue
+		}
+		request, _ := json.Marshal(struct {
+			JsonRPC string          `json:"jsonrpc"`
+			ID      int             `json:"id"`
+			Method  string          `json:"method"`
+			Params  json.RawMessage `json:"params"`
+		}{"2.0", 1, tt.Method, params})
+		response, err := n.send(request)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %v", tt.Method, tt.Name, err)
+		}
+		if err := writeTestFile(outdir, tt, request, response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderParams(tt rpcTestTemplate, funcs template.FuncMap) (json.RawMessage, error) {
+	tmpl, err := template.New(tt.Name).Funcs(funcs).Parse(tt.Params)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+	var params []json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &params); err != nil {
+		return nil, fmt.Errorf("params are not a JSON array: %v", err)
+	}
+	if params == nil {
+		params = []json.RawMessage{}
+	}
+	return json.Marshal(params)
+}
+
+func writeTestFile(outdir string, tt rpcTestTemplate, request, response []byte) error {
+	dir := filepath.Join(outdir, tt.Method)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var resp bytes.Buffer
+	if err := json.Compact(&resp, response); err != nil {
+		return fmt.Errorf("%s/%s: invalid response: %v", tt.Method, tt.Name, err)
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(tt.Comment), "\n") {
+		if line != "" {
+			fmt.Fprintf(&b, "// %s\n", line)
+		}
+	}
+	fmt.Fprintf(&b, ">> %s\n", request)
+	fmt.Fprintf(&b, "<< %s\n", resp.Bytes())
+	file := filepath.Join(dir, tt.Name+".io")
+	fmt.Println("writing", file)
+	return os.WriteFile(file, []byte(b.String()), 0644)
+}
+
+// rpcTestNode is an in-process go-ethereum node serving the chain.
+type rpcTestNode struct {
+	stack *node.Node
+	chain *core.BlockChain
+	url   string
+}
+
+// startRPCTestNode starts a node without networking, imports the blocks and serves the
+// eth namespace over HTTP. The node is an archive node, so the state of all blocks can
+// be queried.
+func startRPCTestNode(genesis *core.Genesis, blocks []*types.Block) (*rpcTestNode, error) {
+	stack, err := node.New(&node.Config{
+		HTTPHost:    "127.0.0.1",
+		HTTPModules: []string{"eth"},
+		P2P:         p2p.Config{NoDiscovery: true, MaxPeers: 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The chain is imported without a consensus client, so go-ethereum must treat
+	// it as already merged. This doesn't change the genesis block.
+	chainConfig := *genesis.Config
+	chainConfig.TerminalTotalDifficultyPassed = true
+	g := *genesis
+	g.Config = &chainConfig
+
+	config := ethconfig.Defaults
+	config.Genesis = &g
+	config.NetworkId = chainConfig.ChainID.Uint64()
+	config.SyncMode = downloader.FullSync
+	config.NoPruning = true
+	config.Preimages = true
+	backend, err := eth.New(stack, &config)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+	filterSystem := filters.NewFilterSystem(backend.APIBackend, filters.Config{
+		LogCacheSize: config.FilterLogCacheSize,
+	})
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "eth",
+		Service:   filters.NewFilterAPI(filterSystem, false),
+	}})
+
+	bc := backend.BlockChain()
+	if i, err := bc.InsertChain(blocks); err != nil {
+		stack.Close()
+		return nil, fmt.Errorf("chain validation error (block %d): %v", blocks[i].Number(), err)
+	}
+	if err := stack.Start(); err != nil {
+		stack.Close()
+		return nil, err
+	}
+	return &rpcTestNode{stack: stack, chain: bc, url: stack.HTTPEndpoint()}, nil
+}
+
+func (n *rpcTestNode) close() {
+	n.stack.Close()
+}
+
+// send posts a JSON-RPC request to the node and returns the response.
+func (n *rpcTestNode) send(request []byte) ([]byte, error) {
+	resp, err := http.Post(n.url, "application/json", bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// templateFuncs returns the functions available in test templates:
+//
+//	head                  number of the head block
+//	headHash              hash of the head block
+//	block N               number N, hex-encoded
+//	blockHash N           hash of block N
+//	tx "modifier" I       hash of the I-th transaction of a modifier in txinfo.json
+//	txBlock "modifier" I  block number of the transaction
+//	txSender "modifier" I sender address of the transaction
+func (n *rpcTestNode) templateFuncs(txinfo map[string][]txInfoEntry) template.FuncMap {
+	bc := n.chain
+	findTx := func(mod string, index int) (*txInfoEntry, error) {
+		txs := txinfo[mod]
+		if index < 0 || index >= len(txs) {
+			return nil, fmt.Errorf("no transaction %d of %s in txinfo.json", index, mod)
+		}
+		return &txs[index], nil
+	}
+	return template.FuncMap{
+		"head": func() string {
+			return hexutil.EncodeUint64(bc.CurrentBlock().Number.Uint64())
+		},
+		"headHash": func() string {
+			return bc.CurrentBlock().Hash().Hex()
+		},
+		"block": func(num uint64) string {
+			return hexutil.EncodeUint64(num)
+		},
+		"blockHash": func(num uint64) (string, error) {
+			header := bc.GetHeaderByNumber(num)
+			if header == nil {
+				return "", fmt.Errorf("block %d not found", num)
+			}
+			return header.Hash().Hex(), nil
+		},
+		"tx": func(mod string, index int) (string, error) {
+			tx, err := findTx(mod, index)
+			if err != nil {
+				return "", err
+			}
+			return tx.TxHash.Hex(), nil
+		},
+		"txBlock": func(mod string, index int) (string, error) {
+			tx, err := findTx(mod, index)
+			if err != nil {
+				return "", err
+			}
+			return tx.Block.String(), nil
+		},
+		"txSender": func(mod string, index int) (string, error) {
+			tx, err := findTx(mod, index)
+			if err != nil {
+				return "", err
+			}
+			return tx.Sender.Hex(), nil
+		},
+	}
+}
+
+// txInfoEntry contains the common fields of transactions in txinfo.json.
+type txInfoEntry struct {
+	TxHash common.Hash    `json:"txhash"`
+	Sender common.Address `json:"sender"`
+	Block  hexutil.Uint64 `json:"block"`
+}
+
+// loadTxInfo reads the transactions of all modifiers from txinfo.json. Modifiers which
+// don't create transactions are ignored. A missing file is not an error.
+func loadTxInfo(file string) (map[string][]txInfoEntry, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var info map[string]json.RawMessage
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid txinfo.json: %v", err)
+	}
+	txs := make(map[string][]txInfoEntry)
+	for mod, raw := range info {
+		var list []txInfoEntry
+		if json.Unmarshal(raw, &list) == nil && len(list) > 0 && list[0].TxHash != (common.Hash{}) {
+			txs[mod] = list
+		}
+	}
+	return txs, nil
+}
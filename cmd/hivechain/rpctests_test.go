@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRPCTests(t *testing.T) {
+	chaindir := t.TempDir()
+	cfg := generatorConfig{
+		txInterval:  1,
+		txCount:     20,
+		chainLength: 10,
+		outputDir:   chaindir,
+		outputs:     []string{"genesis", "chain", "txinfo"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newGenerator(cfg).run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var templates []rpcTestTemplate
+	if err := yaml.Unmarshal(defaultRPCTestTemplates, &templates); err != nil {
+		t.Fatal(err)
+	}
+	templates = append(templates, rpcTestTemplate{
+		Name:   "missing-tx",
+		Method: "eth_getBalance",
+		Params: `["{{txSender "tx-unknown" 0}}", "latest"]`,
+	})
+	outdir := t.TempDir()
+	if err := writeRPCTests(chaindir, outdir, templates); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range templates {
+		file := filepath.Join(outdir, tt.Method, tt.Name+".io")
+		content, err := os.ReadFile(file)
+		if tt.Name == "missing-tx" {
+			if err == nil {
+				t.Errorf("test with missing tx was written")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[0], "// ") || !strings.HasPrefix(lines[1], ">> ") || !strings.HasPrefix(lines[2], "<< ") {
+			t.Fatalf("%s: invalid test file:\n%s", file, content)
+		}
+		var resp struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(lines[2][3:]), &resp); err != nil {
+			t.Fatalf("%s: invalid response: %v", file, err)
+		}
+		if resp.Error != nil || string(resp.Result) == "null" {
+			t.Errorf("%s: unexpected response %s", file, lines[2])
+		}
+		if tt.Method == "eth_blockNumber" && string(resp.Result) != `"0xa"` {
+			t.Errorf("%s: wrong block number %s", file, resp.Result)
+		}
+		if tt.Method == "eth_getTransactionByHash" || tt.Method == "eth_getTransactionReceipt" {
+			var req struct {
+				Params []common.Hash `json:"params"`
+			}
+			var tx struct {
+				Hash   common.Hash `json:"hash"`
+				TxHash common.Hash `json:"transactionHash"`
+			}
+			json.Unmarshal([]byte(lines[1][3:]), &req)
+			json.Unmarshal(resp.Result, &tx)
+			if tx.Hash != req.Params[0] && tx.TxHash != req.Params[0] {
+				t.Errorf("%s: wrong transaction in response %s", file, resp.Result)
+			}
+		}
+		if tt.Method == "eth_getStorageAt" && string(resp.Result) != `"0x0000000000000000000000000000000000000000000000000000000000000002"` {
+			t.Errorf("%s: wrong storage value %s", file, resp.Result)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
+)
+
+// sideChain is a branch that forks off the generated chain.
+type sideChain struct {
+	forkBlock uint64         // number of the last block shared with the canonical chain
+	blocks    []*types.Block // blocks of the branch, starting at forkBlock+1
+}
+
+// generateSideChains creates the configured side branches of the canonical chain. The
+// db must contain the states of all canonical blocks.
+func (g *generator) generateSideChains(db ethdb.Database, engine consensus.Engine, genesis *types.Block, chain []*types.Block) error {
+	for i, fork := range g.cfg.sideChainForks {
+		parent := genesis
+		if fork > 0 {
+			parent = chain[fork-1]
+		}
+		fmt.Printf("generating side chain %d at block %d\n", i+1, fork)
+		blocks, _ := core.GenerateChain(g.genesis.Config, parent, engine, db, g.cfg.sideChainLength, func(index int, gen *core.BlockGen) {
+			g.modifySideBlock(i, index, gen)
+		})
+
+		// Validate the branch by importing it on top of the canonical blocks.
+		full := append(slices.Clone(chain[:fork]), blocks...)
+		bc, err := g.importChain(engine, full)
+		if err != nil {
+			return fmt.Errorf("side chain %d: %v", i+1, err)
+		}
+		bc.Stop()
+		g.sidechains = append(g.sidechains, &sideChain{forkBlock: fork, blocks: blocks})
+	}
+	return nil
+}
+
+// modifySideBlock fills a block of a side chain. Side chain blocks contain a value
+// transfer with a branch-specific amount, which makes them differ from the canonical
+// blocks at the same height.
+func (g *generator) modifySideBlock(branch int, i int, gen *core.BlockGen) {
+	fmt.Println("generating side block", gen.Number())
+	g.setTime(i, gen)
+	if g.genesis.Config.Clique != nil {
+		g.setClique(i, gen)
+	}
+	if merge, ok := g.mergeBlock(); ok && gen.Number().Uint64() >= merge {
+		gen.SetPoS()
+	}
+	g.setParentBeaconRoot(i, gen)
+
+	ctx := &genBlockContext{index: i, block: gen, gen: g}
+	sender := ctx.TxSenderAccount()
+	recipient := pickRecipient(ctx)
+	ctx.AddNewTx(sender, &types.LegacyTx{
+		Nonce:    ctx.AccountNonce(sender.addr),
+		Gas:      params.TxGas,
+		GasPrice: ctx.TxGasFeeCap(),
+		To:       &recipient,
+		Value:    big.NewInt(int64(1000 + branch)),
+	})
+}
+
+// writeSideChains writes the blocks and engine API requests of all side chains.
+// For side chain N, the files are sidechainN.rlp, sidechainN-fcu.json and
+// sidechainN-newpayload.json.
+func (g *generator) writeSideChains() error {
+	for i, sc := range g.sidechains {
+		name := fmt.Sprintf("sidechain%d", i+1)
+		if err := g.writeSideChainRLP(name+".rlp", sc); err != nil {
+			return err
+		}
+
+		fcu := make([]*rpcRequest, 0)
+		np := make([]*rpcRequest, 0)
+		merge, ok := g.mergeBlock()
+		for _, b := range sc.blocks {
+			if ok && b.NumberU64() >= merge {
+				fcu = append(fcu, g.block2fcu(b))
+				np = append(np, g.block2newpayload(b))
+			}
+		}
+		if err := g.writeJSON(name+"-fcu.json", fcu); err != nil {
+			return err
+		}
+		if err := g.writeJSON(name+"-newpayload.json", np); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) writeSideChainRLP(file string, sc *sideChain) error {
+	out, err := g.openOutputFile(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	for _, b := range sc.blocks {
+		if err := b.EncodeRLP(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// chainSpec is a declarative specification of the generated chain. Fields which are
+// not set in the spec keep the value given on the command line.
+type chainSpec struct {
+	ChainID    *big.Int            `json:"chainID"`
+	Length     int                 `json:"length"`
+	BlockTime  uint64              `json:"blockTime"`
+	GasLimit   uint64              `json:"gasLimit"`
+	Seed       int64               `json:"seed"`
+	Clique     bool                `json:"clique"`
+	TxInterval int                 `json:"txInterval"`
+	TxCount    int                 `json:"txCount"`
+	Forks      map[string]forkSpec `json:"forks"`
+	Modifiers  []modifierRange     `json:"modifiers"`
+	Alloc      core.GenesisAlloc   `json:"alloc"`
+
+	StateAccounts  int `json:"stateAccounts"`
+	StateSlots     int `json:"stateSlots"`
+	StateContracts int `json:"stateContracts"`
+	StateBigCode   int `json:"stateBigCode"`
+}
+
+// forkSpec is the activation point of a fork. Block-number-based forks must set Block,
+// time-based forks can set either Block or Time.
+type forkSpec struct {
+	Block *uint64 `json:"block,omitempty"`
+	Time  *uint64 `json:"time,omitempty"`
+}
+
+// modifierRange selects the block modifiers used in blocks From..To. Modifiers are
+// run in proportion to their weight. Modifiers not listed are not used.
+type modifierRange struct {
+	From    uint64         `json:"from"`
+	To      uint64         `json:"to"` // zero means until the end of the chain
+	Weights map[string]int `json:"weights"`
+}
+
+func (r *modifierRange) contains(num uint64) bool {
+	return num >= r.From && (r.To == 0 || num <= r.To)
+}
+
+// loadChainSpec reads a chain spec file. Files ending in .json are decoded as JSON,
+// all others as YAML.
+func loadChainSpec(file string) (*chainSpec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(filepath.Ext(file), ".json") {
+		// The spec is converted to JSON because the genesis types only support
+		// JSON decoding.
+		var obj any
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("invalid chain spec: %v", err)
+		}
+		if data, err = json.Marshal(yamlToJSON(obj)); err != nil {
+			return nil, fmt.Errorf("invalid chain spec: %v", err)
+		}
+	}
+	var spec chainSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid chain spec: %v", err)
+	}
+	return &spec, nil
+}
+
+// yamlToJSON converts maps with non-string keys, which can't be encoded as JSON.
+func yamlToJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = yamlToJSON(elem)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, elem := range v {
+			m[fmt.Sprint(k)] = yamlToJSON(elem)
+		}
+		return m
+	case []any:
+		for i, elem := range v {
+			v[i] = yamlToJSON(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// apply sets the configuration options defined by the spec.
+func (spec *chainSpec) apply(cfg *generatorConfig) error {
+	if spec.ChainID != nil {
+		cfg.chainID = spec.ChainID
+	}
+	if spec.Length != 0 {
+		cfg.chainLength = spec.Length
+	}
+	if spec.BlockTime != 0 {
+		cfg.blockTime = spec.BlockTime
+	}
+	if spec.GasLimit != 0 {
+		cfg.gasLimit = spec.GasLimit
+	}
+	if spec.Seed != 0 {
+		cfg.seed = spec.Seed
+	}
+	if spec.Clique {
+		cfg.clique = true
+	}
+	if spec.TxInterval != 0 {
+		cfg.txInterval = spec.TxInterval
+	}
+	if spec.TxCount != 0 {
+		cfg.txCount = spec.TxCount
+	}
+	if spec.StateAccounts != 0 {
+		cfg.stateAccounts = spec.StateAccounts
+	}
+	if spec.StateSlots != 0 {
+		cfg.stateSlots = spec.StateSlots
+	}
+	if spec.StateContracts != 0 {
+		cfg.stateContracts = spec.StateContracts
+	}
+	if spec.StateBigCode != 0 {
+		cfg.stateBigCode = spec.StateBigCode
+	}
+	cfg.alloc = spec.Alloc
+
+	for i, r := range spec.Modifiers {
+		if r.To != 0 && r.To < r.From {
+			return fmt.Errorf("modifier range %d: 'to' is lower than 'from'", i)
+		}
+		for name, w := range r.Weights {
+			if modRegistry[name] == nil {
+				return fmt.Errorf("modifier range %d: unknown modifier %q", i, name)
+			}
+			if w < 0 {
+				return fmt.Errorf("modifier range %d: negative weight for %s", i, name)
+			}
+		}
+	}
+	cfg.modifiers = spec.Modifiers
+
+	if spec.Forks != nil {
+		cfg.forks = make(map[string]forkSpec, len(spec.Forks))
+		for name, f := range spec.Forks {
+			name = strings.ToLower(name)
+			switch {
+			case slices.Contains(numberBasedForkNames, name):
+				if f.Block == nil || f.Time != nil {
+					return fmt.Errorf("fork %s: must have block number", name)
+				}
+			case slices.Contains(timeBasedForkNames, name):
+				if (f.Block == nil) == (f.Time == nil) {
+					return fmt.Errorf("fork %s: must have either block number or time", name)
+				}
+			default:
+				return fmt.Errorf("unknown fork %q", name)
+			}
+			cfg.forks[name] = f
+		}
+	}
+	return nil
+}
+
+// resolveForks converts block numbers of time-based forks into timestamps. This must be
+// called after the block time is known.
+func (cfg *generatorConfig) resolveForks() error {
+	for name, f := range cfg.forks {
+		if !slices.Contains(timeBasedForkNames, name) {
+			continue
+		}
+		if f.Block != nil {
+			t := cfg.forkTimestamp(*f.Block)
+			cfg.forks[name] = forkSpec{Time: &t}
+		} else if !cfg.checkForkTimestamp(*f.Time) {
+			return fmt.Errorf("fork %s: time %d is not reachable with block time %ds", name, *f.Time, cfg.blockTime)
+		}
+	}
+	if cfg.forks != nil {
+		if err := cfg.createChainConfig().CheckConfigForkOrder(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const testSpec = `
+chainID: 1337
+length: 12
+blockTime: 12
+gasLimit: 30000000
+seed: 7
+txCount: 4
+forks:
+  homestead: {block: 0}
+  tangerinewhistle: {block: 0}
+  spuriousdragon: {block: 0}
+  byzantium: {block: 0}
+  constantinople: {block: 0}
+  petersburg: {block: 0}
+  istanbul: {block: 0}
+  berlin: {block: 0}
+  london: {block: 0}
+  merge: {block: 0}
+  shanghai: {block: 2}
+  cancun: {time: 70}
+modifiers:
+  - from: 1
+    to: 6
+    weights:
+      tx-transfer-eip1559: 2
+  - from: 7
+    weights:
+      tx-blobs: 1
+      tx-transfer-legacy: 1
+alloc:
+  "0x00000000000000000000000000000000000000aa":
+    balance: "0x10"
+    code: "0x6001"
+`
+
+func TestChainSpec(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "chain.yaml")
+	if err := os.WriteFile(specFile, []byte(testSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := loadChainSpec(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := generatorConfig{outputDir: t.TempDir(), outputs: []string{}}
+	if err := spec.apply(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	bc := g.blockchain
+	if id := bc.Config().ChainID.Uint64(); id != 1337 {
+		t.Errorf("wrong chain ID %d", id)
+	}
+	if gl := bc.Genesis().GasLimit(); gl != 30000000 {
+		t.Errorf("wrong genesis gas limit %d", gl)
+	}
+	if *bc.Config().ShanghaiTime != 22 {
+		t.Errorf("wrong shanghai time %d", *bc.Config().ShanghaiTime)
+	}
+	head := bc.CurrentBlock()
+	if head.Number.Uint64() != 12 || head.Time != 144 {
+		t.Errorf("wrong head block %d, time %d", head.Number, head.Time)
+	}
+	state, _ := bc.State()
+	if code := state.GetCode(common.HexToAddress("0xaa")); len(code) != 2 {
+		t.Errorf("wrong code of alloc account: %x", code)
+	}
+
+	for n := uint64(1); n <= 12; n++ {
+		b := bc.GetBlockByNumber(n)
+		if isCancun := b.ExcessBlobGas() != nil; isCancun != (n >= 6) {
+			t.Errorf("block %d: wrong cancun activation", n)
+		}
+		for _, tx := range b.Transactions() {
+			switch {
+			case n <= 6 && tx.Type() != types.DynamicFeeTxType:
+				t.Errorf("block %d: unexpected tx type %d", n, tx.Type())
+			case n > 6 && tx.Type() != types.BlobTxType && tx.Type() != types.LegacyTxType:
+				t.Errorf("block %d: unexpected tx type %d", n, tx.Type())
+			}
+		}
+		if len(b.Transactions()) == 0 {
+			t.Errorf("block %d has no transactions", n)
+		}
+	}
+}
+
+func TestChainSpecErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{"forks: {london: {time: 10}}", "fork london: must have block number"},
+		{"forks: {cancun: {}}", "fork cancun: must have either block number or time"},
+		{"forks: {foo: {block: 1}}", `unknown fork "foo"`},
+		{"modifiers: [{weights: {foo: 1}}]", `modifier range 0: unknown modifier "foo"`},
+	}
+	for _, test := range tests {
+		specFile := filepath.Join(t.TempDir(), "chain.yaml")
+		os.WriteFile(specFile, []byte(test.spec), 0644)
+		spec, err := loadChainSpec(specFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cfg generatorConfig
+		err = spec.apply(&cfg)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("spec %q: wrong error %v", test.spec, err)
+		}
+	}
+
+	// Fork timestamps which can't be reached with the block time are rejected.
+	cfg := generatorConfig{blockTime: 12, forks: map[string]forkSpec{"shanghai": {Time: new(uint64)}, "cancun": {Time: newUint64(24)}}}
+	if _, err := cfg.withDefaults(); err == nil {
+		t.Error("no error for unreachable fork time")
+	}
+}
+
+func newUint64(v uint64) *uint64 { return &v }
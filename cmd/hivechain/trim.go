@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
+)
+
+// trimCommand extracts a range of blocks from a chain.rlp file.
+func trimCommand(args []string) {
+	var (
+		cfg         generatorConfig
+		from        = flag.Uint64("from", 1, "First block to include")
+		to          = flag.Uint64("to", 0, "Last block to include (default: last block of the chain)")
+		genesisFile = flag.String("genesis", "", "Genesis file of the chain (required)")
+		outlist     = flag.String("outputs", "", "Output modules to regenerate for the trimmed chain")
+	)
+	flag.StringVar(&cfg.outputDir, "outdir", ".", "Destination directory of -outputs")
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 2 || *genesisFile == "" {
+		fatalf("Usage: hivechain trim -genesis <genesis.json> [ options ] <chain.rlp> <newchain.rlp>")
+	}
+	cfg.outputs = splitAndTrim(*outlist)
+
+	genesis, err := loadGenesis(*genesisFile)
+	if err != nil {
+		fatal(err)
+	}
+	if err := trimChain(cfg, genesis, flag.Arg(0), flag.Arg(1), *from, *to); err != nil {
+		fatal(err)
+	}
+}
+
+// trimOutputs are the outputs available for trimmed chains. They are computed from
+// the chain and genesis alone. Other outputs depend on information which is only
+// known while generating the chain, like the accounts and transactions. The chain
+// and powchain outputs are not available because the trimmed range of blocks is
+// written to the output file of the command instead.
+var trimOutputs = []string{
+	"genesis", "forkenv", "era1", "headstate", "statestats",
+	"headblock", "fcu", "newpayload", "headfcu", "headnewpayload",
+}
+
+// trimChain writes blocks from..to of chainFile to outFile. The chain is validated by
+// importing it on top of the given genesis block. If to is zero, the trimmed chain ends
+// at the last block of the input chain.
+func trimChain(cfg generatorConfig, genesis *core.Genesis, chainFile, outFile string, from, to uint64) error {
+	for _, name := range cfg.outputs {
+		if !slices.Contains(trimOutputs, name) {
+			return fmt.Errorf("output %s is not available for trimmed chains", name)
+		}
+	}
+	blocks, err := readChain(chainFile)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("%s contains no blocks", chainFile)
+	}
+	last := blocks[len(blocks)-1].NumberU64()
+	if to == 0 {
+		to = last
+	}
+	switch {
+	case from == 0:
+		return errors.New("-from must be at least 1")
+	case from > to:
+		return fmt.Errorf("invalid range: -from %d is after -to %d", from, to)
+	case to > last:
+		return fmt.Errorf("chain ends at block %d", last)
+	}
+	for len(blocks) > 0 && blocks[len(blocks)-1].NumberU64() > to {
+		blocks = blocks[:len(blocks)-1]
+	}
+
+	// Validate the chain up to the last block of the range.
+	g := &generator{cfg: cfg, genesis: genesis}
+	engine := g.createConsensusEngine(rawdb.NewMemoryDatabase())
+	bc, err := g.importChain(engine, blocks)
+	if err != nil {
+		return err
+	}
+	defer bc.Stop()
+	g.blockchain = bc
+
+	out, err := os.OpenFile(outFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := exportN(bc, out, from, to); err != nil {
+		return err
+	}
+	return g.write()
+}
+
+// readChain reads all blocks of a chain.rlp file.
+func readChain(file string) ([]*types.Block, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocks []*types.Block
+	s := rlp.NewStream(bufio.NewReader(f), 0)
+	for i := 0; ; i++ {
+		var block types.Block
+		err := s.Decode(&block)
+		if err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: block %d: %v", file, i, err)
+		}
+		blocks = append(blocks, &block)
+	}
+}
+
+// loadGenesis reads a genesis.json file.
+func loadGenesis(file string) (*core.Genesis, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var genesis core.Genesis
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %v", err)
+	}
+	if genesis.Config == nil {
+		return nil, errors.New("genesis file has no chain config")
+	}
+	return &genesis, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// chainRecord is the content of config.json. It records the generator configuration
+// and the hashes of all output files.
+type chainRecord struct {
+	Config configRecord           `json:"config"`
+	Files  map[string]common.Hash `json:"files"`
+}
+
+// configRecord is the recorded generator configuration.
+type configRecord struct {
+	chainSpec
+	ForkInterval    int      `json:"forkInterval"`
+	LastFork        string   `json:"lastFork"`
+	SideChains      []uint64 `json:"sideChains,omitempty"`
+	SideChainLength int      `json:"sideChainLength,omitempty"`
+	Outputs         []string `json:"outputs"`
+}
+
+func newConfigRecord(cfg generatorConfig) configRecord {
+	outputs := slices.Clone(cfg.outputs)
+	outputs = slices.DeleteFunc(outputs, func(s string) bool { return s == "config" })
+	return configRecord{
+		chainSpec: chainSpec{
+			ChainID:    cfg.chainID,
+			Length:     cfg.chainLength,
+			BlockTime:  cfg.blockTime,
+			GasLimit:   cfg.gasLimit,
+			Seed:       cfg.seed,
+			Clique:     cfg.clique,
+			TxInterval: cfg.txInterval,
+			TxCount:    cfg.txCount,
+			Forks:      cfg.forks,
+			Modifiers:  cfg.modifiers,
+			Alloc:      cfg.alloc,
+
+			StateAccounts:  cfg.stateAccounts,
+			StateSlots:     cfg.stateSlots,
+			StateContracts: cfg.stateContracts,
+			StateBigCode:   cfg.stateBigCode,
+		},
+		ForkInterval:    cfg.forkInterval,
+		LastFork:        cfg.lastFork,
+		SideChains:      cfg.sideChainForks,
+		SideChainLength: cfg.sideChainLength,
+		Outputs:         outputs,
+	}
+}
+
+// config returns the generator configuration.
+func (r *configRecord) config() (generatorConfig, error) {
+	var cfg generatorConfig
+	if err := r.chainSpec.apply(&cfg); err != nil {
+		return cfg, err
+	}
+	cfg.forkInterval = r.ForkInterval
+	cfg.lastFork = r.LastFork
+	cfg.sideChainForks = r.SideChains
+	cfg.sideChainLength = r.SideChainLength
+	cfg.outputs = r.Outputs
+	return cfg, nil
+}
+
+// writeConfig writes config.json. This must run after all other outputs.
+func (g *generator) writeConfig() error {
+	rec := chainRecord{
+		Config: newConfigRecord(g.cfg),
+		Files:  make(map[string]common.Hash),
+	}
+	for _, file := range g.outputFiles {
+		h, err := hashFile(filepath.Join(g.cfg.outputDir, file))
+		if err != nil {
+			return err
+		}
+		rec.Files[file] = h
+	}
+	return g.writeJSON("config.json", &rec)
+}
+
+func hashFile(file string) (common.Hash, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// verifyCommand checks that the output files in a directory can be reproduced.
+func verifyCommand(args []string) {
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 1 {
+		fatalf("Usage: hivechain verify <dir>")
+	}
+	n, err := verifyChain(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("verified %d files\n", n)
+}
+
+// verifyChain checks the output files in dir against the hashes recorded in config.json,
+// then re-generates the chain and checks that the output is identical.
+func verifyChain(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return 0, err
+	}
+	var rec chainRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return 0, fmt.Errorf("invalid config.json: %v", err)
+	}
+	files := maps.Keys(rec.Files)
+	sort.Strings(files)
+
+	// Check the files haven't been modified after generating them.
+	for _, file := range files {
+		h, err := hashFile(filepath.Join(dir, file))
+		if err != nil {
+			return 0, err
+		}
+		if h != rec.Files[file] {
+			return 0, fmt.Errorf("%s does not match the hash in config.json", file)
+		}
+	}
+
+	// Re-generate the chain.
+	cfg, err := rec.Config.config()
+	if err != nil {
+		return 0, fmt.Errorf("invalid config.json: %v", err)
+	}
+	tmpdir, err := os.MkdirTemp("", "hivechain-verify-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmpdir)
+	cfg.outputDir = tmpdir
+	if cfg, err = cfg.withDefaults(); err != nil {
+		return 0, err
+	}
+	if err := newGenerator(cfg).run(); err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		h, err := hashFile(filepath.Join(tmpdir, file))
+		if err != nil {
+			return 0, fmt.Errorf("re-generated output is missing %s", file)
+		}
+		if h != rec.Files[file] {
+			return 0, fmt.Errorf("re-generated %s differs", file)
+		}
+	}
+	return len(files), nil
+}
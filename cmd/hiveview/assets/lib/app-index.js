@@ -42,6 +42,7 @@ function showFileListing(data) {
             "simLog": "1587325280-00befe48086b1ef74fbb19b9b7d43e4d-simulator.log",
             "passes": 0,
             "fails": 0,
+            "skips": 0,
             "size": 435,
             "clients": [],
             "description": "This suite of tests verifies that clients can sync from each...'\n",
@@ -114,11 +115,15 @@ function showFileListing(data) {
                 width: '5.5em',
                 className: 'suite-status-column',
                 render: function(data) {
+                    let skipped = '';
+                    if (data.skips > 0) {
+                        skipped = ' <span class="skipped">&#x2298; ' + data.skips + '</span>';
+                    }
                     if (data.fails > 0) {
                         let prefix = data.timeout ? 'Timeout' : 'Fail';
-                        return '&#x2715; <b>' + prefix + ' (' + data.fails + ' / ' + (data.fails + data.passes) + ')</b>';
+                        return '&#x2715; <b>' + prefix + ' (' + data.fails + ' / ' + (data.fails + data.passes) + ')</b>' + skipped;
                     }
-                    return '&#x2713 (' + data.passes + ')';
+                    return '&#x2713 (' + data.passes + ')' + skipped;
                 },
             },
             {
@@ -364,6 +369,7 @@ class StatusFilter extends ColumnFilter {
         select.append($('<option value="SUCCESS">SUCCESS</option>'));
         select.append($('<option value="FAIL">FAIL</option>'));
         select.append($('<option value="TIMEOUT">TIMEOUT</option>'));
+        select.append($('<option value="SKIPPED">SKIPPED</option>'));
         return select;
     }
 
@@ -371,6 +377,9 @@ class StatusFilter extends ColumnFilter {
         if (value === 'SUCCESS') {
             return '✓';
         }
+        if (value === 'SKIPPED') {
+            return '⊘';
+        }
         return escapeRegExp(value);
     }
 }
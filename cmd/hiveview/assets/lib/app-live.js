@@ -0,0 +1,170 @@
+import $ from 'jquery';
+
+import * as common from './app-common.js';
+import * as routes from './routes.js';
+import { makeLink } from './html.js';
+import { formatDuration } from './utils.js';
+
+// Polling intervals in milliseconds.
+const runsInterval = 5000;
+const logInterval = 2000;
+
+// Maximum number of log characters kept in the log view.
+const maxLogLength = 1000000;
+
+$(document).ready(function () {
+    common.updateHeader();
+    $('#live-log-close').click(function () {
+        logView.stop();
+    });
+    pollRuns();
+});
+
+// pollRuns fetches the state of in-progress runs periodically.
+function pollRuns() {
+    $.ajax({
+        type: 'GET',
+        url: 'live.json',
+        dataType: 'json',
+        cache: false,
+        success: showRuns,
+        error: function(xhr, status, error) {
+            console.log('error fetching live.json:', error);
+        },
+        complete: function () {
+            setTimeout(pollRuns, runsInterval);
+        },
+    });
+}
+
+// showRuns renders the run list.
+function showRuns(runs) {
+    $('#live-empty').toggle(runs.length == 0);
+    let container = $('#live-runs');
+    container.empty();
+    let now = new Date();
+    runs.forEach(function (run) {
+        container.append(runHTML(run, now));
+    });
+}
+
+function runHTML(run, now) {
+    let div = $('<div class="live-run"></div>');
+    let title = $('<h4></h4>');
+    title.text(run.sim + ' ');
+    let simlog = makeLink(routes.simulatorLog('', run.sim, run.simLog), 'simulator log');
+    title.append($('<small></small>').append(simlog));
+    div.append(title);
+
+    let elapsed = now - new Date(run.start);
+    div.append($('<p></p>').text('Running for ' + formatDuration(elapsed)));
+
+    run.suites.forEach(function (suite) {
+        div.append(suiteHTML(suite, now));
+    });
+    return div;
+}
+
+function suiteHTML(suite, now) {
+    let div = $('<div class="live-suite"></div>');
+    let status = suite.ended ? 'ended' : 'running';
+    let header = $('<h5></h5>').text(suite.name);
+    let counts = ' ✓ ' + suite.passes + ' / ✕ ' + suite.fails;
+    if (suite.skips > 0) {
+        counts += ' / ⊘ ' + suite.skips;
+    }
+    counts += ' (' + status + ')';
+    header.append($('<small></small>').text(counts));
+    div.append(header);
+    if (suite.running.length == 0) {
+        return div;
+    }
+
+    let table = $('<table class="table table-bordered table-sm"></table>');
+    table.append('<thead><tr><th>Test</th><th>Elapsed</th><th>Clients</th></tr></thead>');
+    let tbody = $('<tbody></tbody>');
+    suite.running.forEach(function (test) {
+        let row = $('<tr></tr>');
+        row.append($('<td></td>').text(test.name));
+        row.append($('<td class="test-duration-column"></td>').text(formatDuration(now - new Date(test.start))));
+        let clients = $('<td></td>');
+        test.clients.forEach(function (client) {
+            let link = $('<a href="#" class="live-client-link"></a>').text(client.name);
+            link.click(function () {
+                logView.start(client.name + ' (' + client.id.substring(0, 8) + ')', client.logFile);
+                return false;
+            });
+            clients.append(link).append(' ');
+        });
+        row.append(clients);
+        tbody.append(row);
+    });
+    table.append(tbody);
+    div.append(table);
+    return div;
+}
+
+// logView streams a log file by polling for new content.
+let logView = {
+    file: null,
+    offset: 0,
+    timer: null,
+    decoder: null,
+
+    start(name, file) {
+        this.stop();
+        this.file = file;
+        this.offset = 0;
+        this.decoder = new TextDecoder();
+        $('#live-log-name').text(name);
+        $('#live-log-content').text('');
+        $('#live-log').show();
+        this.poll();
+    },
+
+    stop() {
+        this.file = null;
+        clearTimeout(this.timer);
+        $('#live-log').hide();
+    },
+
+    async poll() {
+        const file = this.file;
+        if (!file) {
+            return;
+        }
+        try {
+            let options = {headers: {'Range': 'bytes=' + this.offset + '-'}};
+            let response = await fetch(routes.resultsRoot + file, options);
+            if (response.status == 206 || (response.status == 200 && this.offset == 0)) {
+                let data = await response.arrayBuffer();
+                if (file === this.file) {
+                    this.offset += data.byteLength;
+                    this.append(this.decoder.decode(data, {stream: true}));
+                }
+            } else if (response.status != 416) {
+                // 416 means there is no new content.
+                console.log('error fetching log:', response.status);
+            }
+        } catch (err) {
+            console.log('error fetching log:', err);
+        }
+        if (file === this.file) {
+            this.timer = setTimeout(this.poll.bind(this), logInterval);
+        }
+    },
+
+    append(text) {
+        let pre = $('#live-log-content');
+        let el = pre.get(0);
+        let atBottom = el.scrollTop + el.clientHeight >= el.scrollHeight - 10;
+        let content = pre.text() + text;
+        if (content.length > maxLogLength) {
+            content = content.substring(content.length - maxLogLength);
+        }
+        pre.text(content);
+        if (atBottom) {
+            el.scrollTop = el.scrollHeight;
+        }
+    },
+};
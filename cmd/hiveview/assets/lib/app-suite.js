@@ -8,7 +8,7 @@ import * as common from './app-common.js';
 import * as routes from './routes.js';
 import * as html from './html.js';
 import * as testlog from './testlog.js';
-import { formatDuration, queryParam } from './utils.js';
+import { formatBytes, formatDuration, queryParam } from './utils.js';
 
 $(document).ready(function () {
     common.updateHeader();
@@ -120,6 +120,16 @@ function showSuiteData(data, suiteID) {
     }
     console.log('got ' + cases.length + ' testcases');
 
+    // Arrange subtests below their parent tests.
+    let hasTree = buildTestTree(cases);
+    let collapsed = {};
+    $.fn.dataTable.ext.search.push(function (settings, searchData, dataIndex) {
+        if (settings.nTable.id !== 'execresults') {
+            return true;
+        }
+        return !hiddenByParent(cases[dataIndex], collapsed);
+    });
+
     // Fill info box.
     let suiteTimes = testSuiteTimes(cases);
     $('#testsuite_start').html('🕒 ' + suiteTimes.start.toLocaleString());
@@ -143,7 +153,7 @@ function showSuiteData(data, suiteID) {
                 display: function (row, update, render) {},
             },
         },
-        order: [[1, 'desc']],
+        order: hasTree ? [[4, 'asc']] : [[1, 'desc']],
         columns: [
             {
                 title: 'Test',
@@ -152,6 +162,12 @@ function showSuiteData(data, suiteID) {
                 className: 'test-name-column',
                 width: '65%',
                 responsivePriority: 0,
+                render: function (name, type, row) {
+                    if (type !== 'display' || !hasTree) {
+                        return name;
+                    }
+                    return formatTreeName(name, row, collapsed);
+                },
             },
             // Status: pass or not.
             {
@@ -190,10 +206,19 @@ function showSuiteData(data, suiteID) {
                     return formatClientLogsList(data, row.testIndex, clientInfo);
                 }
             },
+            // Position in the test tree.
+            {
+                data: 'treeOrder',
+                name: 'tree',
+                visible: false,
+                type: 'num',
+            },
         ],
         rowCallback: function(row, data, displayNum, displayIndex, dataIndex) {
             if (!cases[dataIndex].summaryResult.pass) {
                 row.classList.add('failed');
+            } else if (cases[dataIndex].summaryResult.skipped) {
+                row.classList.add('skipped');
             }
         },
     });
@@ -204,6 +229,96 @@ function showSuiteData(data, suiteID) {
         let tr = $(this).closest('tr');
         toggleTestDetails(data, table, tr);
     });
+
+    // This expands/collapses the subtests of a test.
+    $('#execresults tbody').on('click', '.tree-toggle', function(ev) {
+        ev.stopPropagation();
+        let row = table.row($(this).closest('tr'));
+        let index = row.data().testIndex;
+        collapsed[index] = !collapsed[index];
+        row.invalidate();
+        table.draw(false);
+    });
+}
+
+// buildTestTree links test cases to their parent tests. It sets the depth, treeOrder,
+// subtests and subtestCounts properties of each case and returns true if any test
+// has a parent.
+function buildTestTree(cases) {
+    let byIndex = {};
+    cases.forEach(function (tc) {
+        tc.subtests = [];
+        byIndex[tc.testIndex] = tc;
+    });
+
+    let roots = [];
+    let hasTree = false;
+    cases.forEach(function (tc) {
+        let parent = tc.parent ? byIndex[tc.parent] : undefined;
+        if (parent) {
+            parent.subtests.push(tc);
+            tc.parentCase = parent;
+            hasTree = true;
+        } else {
+            roots.push(tc);
+        }
+    });
+
+    // Assign positions in depth-first order and aggregate the results of subtests.
+    let byIndexOrder = function (a, b) { return a.testIndex - b.testIndex; };
+    let position = 0;
+    let visit = function (tc, depth) {
+        tc.depth = depth;
+        tc.treeOrder = position++;
+        tc.subtestCounts = { passes: 0, fails: 0, skips: 0 };
+        tc.subtests.sort(byIndexOrder);
+        tc.subtests.forEach(function (sub) {
+            let counts = visit(sub, depth + 1);
+            tc.subtestCounts.passes += counts.passes;
+            tc.subtestCounts.fails += counts.fails;
+            tc.subtestCounts.skips += counts.skips;
+        });
+        let total = Object.assign({}, tc.subtestCounts);
+        total[resultKind(tc.summaryResult)]++;
+        return total;
+    };
+    roots.sort(byIndexOrder);
+    roots.forEach(function (tc) { visit(tc, 0); });
+    return hasTree;
+}
+
+// resultKind returns the counter name for a test result.
+function resultKind(summaryResult) {
+    if (summaryResult.skipped) {
+        return 'skips';
+    }
+    return summaryResult.pass ? 'passes' : 'fails';
+}
+
+// hiddenByParent reports whether any parent of the test is collapsed.
+function hiddenByParent(tc, collapsed) {
+    for (let p = tc.parentCase; p; p = p.parentCase) {
+        if (collapsed[p.testIndex]) {
+            return true;
+        }
+    }
+    return false;
+}
+
+// formatTreeName renders the test name with indentation and subtest counts.
+function formatTreeName(name, tc, collapsed) {
+    let indent = '<span class="tree-indent" style="width: ' + (tc.depth * 1.5) + 'em"></span>';
+    if (tc.subtests.length == 0) {
+        return indent + '<span class="tree-toggle-space"></span>' + name;
+    }
+    let arrow = collapsed[tc.testIndex] ? '&#x25B8;' : '&#x25BE;';
+    let c = tc.subtestCounts;
+    let counts = '&#x2713; ' + c.passes + ' / &#x2715; ' + c.fails;
+    if (c.skips > 0) {
+        counts += ' / &#x2298; ' + c.skips;
+    }
+    return indent + '<span class="tree-toggle" title="Show/hide subtests">' + arrow + '</span>' +
+        name + ' <span class="tree-counts">(' + counts + ')</span>';
 }
 
 // testSuiteTimes computes start/end/duration of a test suite.
@@ -321,7 +436,21 @@ function formatClientLogsList(suiteData, testIndex, clientInfo) {
     return links.join(', ');
 }
 
+// formatArtifactsList turns the artifacts of a test into a list of download links.
+function formatArtifactsList(artifacts) {
+    let links = artifacts.map(function (a) {
+        let link = html.makeLink(routes.resultsRoot + a.file, a.name);
+        link.setAttribute('download', a.name);
+        link.classList.add('artifact-link');
+        return link.outerHTML + ' (' + formatBytes(a.size) + ')';
+    });
+    return links.join(', ');
+}
+
 function formatTestStatus(summaryResult) {
+    if (summaryResult.skipped) {
+        return '<span class="skipped">&#x2298; Skip</span>';
+    }
     if (summaryResult.pass) {
         return '&#x2713';
     }
@@ -357,6 +486,12 @@ function formatTestDetails(suiteData, row) {
         container.appendChild(p);
     }
 
+    if (d.artifacts && d.artifacts.length > 0) {
+        let p = document.createElement('p');
+        p.innerHTML = '<b>Artifacts:</b> ' + formatArtifactsList(d.artifacts);
+        container.appendChild(p);
+    }
+
     if (d.description != '') {
         let p = document.createElement('p');
         let description = html.urlsToLinks(html.encode(d.description.trim()));
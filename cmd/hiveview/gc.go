@@ -1,81 +1,388 @@
 package main
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/hive/internal/libhive"
 )
 
-func logdirGC(dir string, cutoff time.Time, keepMin int) error {
+// gcConfig is the retention policy of logdirGC.
+type gcConfig struct {
+	// Suites started before cutoff are deleted, unless
+	// they are retained by one of the other rules.
+	cutoff time.Time
+
+	// Minimum number of suites to keep, regardless of age and quota.
+	keepMin int
+
+	// Number of recent runs to keep for each combination of suite and clients.
+	keepRuns int
+
+	// Suites with failed tests started after this time are kept.
+	keepFailing time.Time
+
+	// Maximum total size of kept files. Zero means unlimited.
+	quota int64
+
+	// Client logs of kept suites started before this time are compressed.
+	compressBefore time.Time
+
+	// When set, files are not modified and only the report is printed.
+	dryRun bool
+}
+
+// gcSuite is a suite file considered by the garbage collector.
+type gcSuite struct {
+	file      string
+	start     time.Time
+	runKey    string
+	failed    bool
+	files     []string // all files referenced by the suite, including file
+	clientLog []string // client logs referenced by the suite
+}
+
+// gcReport summarizes the actions taken by logdirGC.
+type gcReport struct {
+	keptSuites    int
+	deletedSuites int
+	keptFiles     int
+	oldest        time.Time
+
+	deletedFiles    int
+	deletedBytes    int64
+	compressedFiles int
+	compressedBytes int64 // size reduction through compression
+	errors          int
+}
+
+func logdirGC(dir string, cfg gcConfig) (*gcReport, error) {
 	var (
 		fsys       = os.DirFS(dir)
+		suites     []*gcSuite
 		usedFiles  = make(map[string]struct{})
-		keptSuites = 0
-		oldest     time.Time
+		suiteFiles = make(map[string]struct{})
+		report     = new(gcReport)
 	)
 
 	// Avoid deleting the status/version file.
 	usedFiles["hive.json"] = struct{}{}
 
-	// Walk all suite files and pouplate the usedFiles set.
+	// Collect all suites. Note walkSummaryFiles reports suites in descending time order.
 	err := walkSummaryFiles(fsys, ".", func(suite *libhive.TestSuite, fi fs.FileInfo) error {
-		// Skip when too old and when above the minimum.
-		// Note we rely on getting called in descending time order here.
-		if suiteStart(suite).Before(cutoff) && keptSuites >= keepMin {
-			return nil
+		s := newGCSuite(suite, fi.Name())
+		for _, f := range s.files {
+			suiteFiles[f] = struct{}{}
 		}
-		if oldest.IsZero() || suiteStart(suite).Before(oldest) {
-			oldest = suiteStart(suite)
+		suites = append(suites, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply retention rules and add files of kept suites to usedFiles.
+	var (
+		runCount  = make(map[string]int)
+		keptSize  int64
+		compress  []string
+		sizeCache = make(map[string]int64)
+	)
+	for _, s := range suites {
+		runCount[s.runKey]++
+		keep := report.keptSuites < cfg.keepMin || s.retained(&cfg, runCount[s.runKey])
+		if keep && cfg.quota > 0 && report.keptSuites >= cfg.keepMin {
+			size := s.newFilesSize(dir, usedFiles, sizeCache)
+			keep = keptSize+size <= cfg.quota
+		}
+		if !keep {
+			report.deletedSuites++
+			continue
 		}
 
-		// Add suite files and client logs.
-		keptSuites++
-		usedFiles[fi.Name()] = struct{}{}
-		usedFiles[suite.SimulatorLog] = struct{}{}
-		if suite.TestDetailsLog != "" {
-			usedFiles[suite.TestDetailsLog] = struct{}{}
+		keptSize += s.newFilesSize(dir, usedFiles, sizeCache)
+		report.keptSuites++
+		if report.oldest.IsZero() || s.start.Before(report.oldest) {
+			report.oldest = s.start
 		}
-		for _, test := range suite.TestCases {
-			for _, client := range test.ClientInfo {
-				usedFiles[client.LogFile] = struct{}{}
-			}
+		for _, f := range s.files {
+			usedFiles[f] = struct{}{}
 		}
-		return nil
-	})
+		if !cfg.compressBefore.IsZero() && s.start.Before(cfg.compressBefore) {
+			compress = append(compress, s.clientLog...)
+		}
+	}
+
+	// Keep the files of runs which are still in progress. Their suite
+	// files are only written when the suite ends.
+	runs, err := readLiveRuns(fsys, time.Now())
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, run := range runs {
+		usedFiles[run.File] = struct{}{}
+		for _, f := range run.files {
+			usedFiles[f] = struct{}{}
+		}
 	}
+	report.keptFiles = len(usedFiles)
 
-	fmt.Printf("keeping %d suites (%d files)\n", keptSuites, len(usedFiles))
-	fmt.Println("oldest suite date:", oldest)
+	// Compress old client logs. Compressed logs are stored with a .gz suffix
+	// and decompressed on the fly when serving.
+	for _, file := range compress {
+		if strings.HasSuffix(file, ".gz") {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		saved, err := compressFile(path, cfg.dryRun)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				fmt.Println("error:", err)
+				report.errors++
+			}
+			continue
+		}
+		if cfg.dryRun {
+			fmt.Println("gzip", file)
+		}
+		report.compressedFiles++
+		report.compressedBytes += saved
+	}
 
 	// Delete all files which aren't in usedFiles.
-	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // Ignore scan errors.
 		}
 		if d.IsDir() {
 			return nil // Don't delete directories.
 		}
-		if _, used := usedFiles[path]; !used {
+		if _, used := usedFiles[strings.TrimSuffix(path, ".gz")]; used {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		_, inSuite := suiteFiles[strings.TrimSuffix(path, ".gz")]
+		if !inSuite && info.ModTime().After(cfg.cutoff) {
+			return nil // Keep recent files which don't belong to any suite yet.
+		}
+		if cfg.dryRun {
+			fmt.Println("rm", path)
+		} else {
 			file := filepath.Join(dir, filepath.FromSlash(path))
-			// fmt.Println("rm", file)
-			err := os.Remove(file)
-			if err != nil {
+			if err := os.Remove(file); err != nil {
 				fmt.Println("error:", err)
+				report.errors++
+				return nil
 			}
 		}
+		report.deletedFiles++
+		report.deletedBytes += info.Size()
 		return nil
 	})
+	return report, err
+}
+
+func newGCSuite(suite *libhive.TestSuite, file string) *gcSuite {
+	s := &gcSuite{file: file, start: suiteStart(suite)}
+	s.files = append(s.files, file, suite.SimulatorLog)
+	if suite.TestDetailsLog != "" {
+		s.files = append(s.files, suite.TestDetailsLog)
+	}
+	var clients []string
+	for _, test := range suite.TestCases {
+		if !test.SummaryResult.Pass {
+			s.failed = true
+		}
+		for _, a := range test.Artifacts {
+			s.files = append(s.files, a.File)
+		}
+		for _, client := range test.ClientInfo {
+			s.files = append(s.files, client.LogFile)
+			s.clientLog = append(s.clientLog, client.LogFile)
+			if !contains(clients, client.Name) {
+				clients = append(clients, client.Name)
+			}
+		}
+	}
+	sort.Strings(clients)
+	s.runKey = suite.Name + "/" + strings.Join(clients, ",")
+	return s
+}
+
+// retained reports whether the suite is kept by the retention rules.
+// run is the position of the suite among runs with the same key, starting at 1.
+func (s *gcSuite) retained(cfg *gcConfig, run int) bool {
+	switch {
+	case !s.start.Before(cfg.cutoff):
+		return true
+	case cfg.keepRuns > 0 && run <= cfg.keepRuns:
+		return true
+	case s.failed && !cfg.keepFailing.IsZero() && !s.start.Before(cfg.keepFailing):
+		return true
+	default:
+		return false
+	}
+}
+
+// newFilesSize computes the size of suite files which are not in used yet.
+func (s *gcSuite) newFilesSize(dir string, used map[string]struct{}, cache map[string]int64) int64 {
+	var total int64
+	for _, f := range s.files {
+		if _, ok := used[f]; ok {
+			continue
+		}
+		size, ok := cache[f]
+		if !ok {
+			size = fileSize(filepath.Join(dir, filepath.FromSlash(f)))
+			cache[f] = size
+		}
+		total += size
+	}
+	return total
+}
+
+func fileSize(file string) int64 {
+	if stat, err := os.Stat(file); err == nil {
+		return stat.Size()
+	}
+	if stat, err := os.Stat(file + ".gz"); err == nil {
+		return stat.Size()
+	}
+	return 0
 }
 
+// compressFile replaces file by file.gz, returning the size reduction.
+// If dryRun is set, the size of the compressed file is computed, but nothing
+// is written.
+func compressFile(file string, dryRun bool) (int64, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	stat, err := in.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		out io.Writer
+		f   *os.File
+		cw  = new(countingWriter)
+		tmp = file + ".gz.tmp"
+	)
+	if dryRun {
+		out = cw
+	} else {
+		f, err = os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return 0, err
+		}
+		defer os.Remove(tmp)
+		defer f.Close()
+		out = io.MultiWriter(f, cw)
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	if !dryRun {
+		if err := f.Close(); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(tmp, file+".gz"); err != nil {
+			return 0, err
+		}
+		in.Close()
+		if err := os.Remove(file); err != nil {
+			return 0, err
+		}
+	}
+	return stat.Size() - cw.n, nil
+}
+
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.n += int64(len(b))
+	return len(b), nil
+}
+
+// suiteStart returns the start time of the earliest test in the suite.
 func suiteStart(suite *libhive.TestSuite) time.Time {
+	var start time.Time
 	for _, test := range suite.TestCases {
-		return test.Start
+		if start.IsZero() || test.Start.Before(start) {
+			start = test.Start
+		}
+	}
+	return start
+}
+
+func (r *gcReport) print(w io.Writer, dryRun bool) {
+	verb := ""
+	if dryRun {
+		verb = " (dry run)"
+	}
+	fmt.Fprintf(w, "keeping %d suites (%d files), deleting %d suites%s\n", r.keptSuites, r.keptFiles, r.deletedSuites, verb)
+	fmt.Fprintln(w, "oldest suite date:", r.oldest)
+	fmt.Fprintf(w, "deleted %d files, reclaimed %s\n", r.deletedFiles, formatSize(r.deletedBytes))
+	fmt.Fprintf(w, "compressed %d client logs, reclaimed %s\n", r.compressedFiles, formatSize(r.compressedBytes))
+	fmt.Fprintf(w, "total reclaimed space: %s\n", formatSize(r.deletedBytes+r.compressedBytes))
+	if r.errors > 0 {
+		fmt.Fprintf(w, "%d errors\n", r.errors)
+	}
+}
+
+var sizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+func formatSize(n int64) string {
+	for _, u := range sizeUnits {
+		if n >= u.size && u.size > 1 {
+			return fmt.Sprintf("%.2f %s", float64(n)/float64(u.size), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// parseSize parses a size like "500MB" or "20GB".
+func parseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(str, u.suffix)), 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid size %q", s)
+			}
+			return int64(n * float64(u.size)), nil
+		}
+	}
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
 	}
-	return time.Time{}
+	return n, nil
 }
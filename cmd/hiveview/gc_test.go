@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+type gcTestSuite struct {
+	name   string
+	client string
+	age    time.Duration
+	pass   bool
+}
+
+// writeGCTestDir creates a results directory with the given suites. Suites must be
+// given oldest-first. It returns the client log file names of all suites.
+func writeGCTestDir(t *testing.T, now time.Time, suites []gcTestSuite) (string, []string) {
+	dir := t.TempDir()
+	var logs []string
+	for i, s := range suites {
+		start := now.Add(-s.age)
+		var (
+			simLog    = fmt.Sprintf("%d-simulator-%d.log", start.Unix(), i)
+			clientLog = fmt.Sprintf("%s/client-%d.log", s.client, i)
+			suiteFile = fmt.Sprintf("%d-%d.json", start.Unix(), i)
+		)
+		suite := libhive.TestSuite{
+			Name:         s.name,
+			SimulatorLog: simLog,
+			TestCases: map[libhive.TestID]*libhive.TestCase{
+				1: {
+					Name:          "test",
+					Start:         start,
+					SummaryResult: libhive.TestResult{Pass: s.pass},
+					ClientInfo: map[string]*libhive.ClientInfo{
+						"c": {Name: s.client, LogFile: clientLog},
+					},
+				},
+			},
+		}
+		suiteJSON, _ := json.Marshal(&suite)
+		os.MkdirAll(filepath.Join(dir, s.client), 0755)
+		os.WriteFile(filepath.Join(dir, suiteFile), suiteJSON, 0644)
+		os.WriteFile(filepath.Join(dir, simLog), []byte("sim"), 0644)
+		os.WriteFile(filepath.Join(dir, filepath.FromSlash(clientLog)), make([]byte, 1000), 0644)
+		logs = append(logs, clientLog)
+	}
+	return dir, logs
+}
+
+func exists(dir, file string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+	return err == nil
+}
+
+func TestGCRetention(t *testing.T) {
+	var (
+		now    = time.Now()
+		day    = 24 * time.Hour
+		suites = []gcTestSuite{
+			{name: "a", client: "geth", age: 50 * day, pass: false},
+			{name: "a", client: "geth", age: 40 * day, pass: true},
+			{name: "a", client: "besu", age: 30 * day, pass: true},
+			{name: "b", client: "geth", age: 20 * day, pass: true},
+			{name: "a", client: "geth", age: 1 * day, pass: true},
+		}
+	)
+
+	t.Run("cutoff", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		report, err := logdirGC(dir, gcConfig{cutoff: now.Add(-25 * day)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{false, false, false, true, true})
+		if report.keptSuites != 2 || report.deletedSuites != 3 {
+			t.Fatalf("wrong report: %+v", report)
+		}
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		report, err := logdirGC(dir, gcConfig{cutoff: now, dryRun: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{true, true, true, true, true})
+		if report.deletedFiles != 15 || report.deletedBytes == 0 {
+			t.Fatalf("wrong report: %+v", report)
+		}
+	})
+
+	t.Run("keep-runs", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		_, err := logdirGC(dir, gcConfig{cutoff: now, keepRuns: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{false, true, true, true, true})
+	})
+
+	t.Run("keep-failing", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		_, err := logdirGC(dir, gcConfig{cutoff: now.Add(-10 * day), keepFailing: now.Add(-60 * day)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{true, false, false, false, true})
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		_, err := logdirGC(dir, gcConfig{cutoff: now.Add(-100 * day), quota: 3000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{false, false, false, true, true})
+	})
+
+	t.Run("in-progress", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		var (
+			old      = now.Add(-2 * day)
+			eventLog = libhive.EventsDir + "/1-running.jsonl"
+			events   = []libhive.Event{
+				{Type: libhive.EventSimStarted, Time: old, Name: "sim", SimLog: "running-sim.log"},
+				{Type: libhive.EventSuiteStarted, Time: old, Name: "a"},
+				{Type: libhive.EventTestStarted, Time: old, Test: 1, Name: "test"},
+				{Type: libhive.EventClientStarted, Time: old, Test: 1, Client: &libhive.ClientInfo{Name: "geth", LogFile: "geth/running.log"}},
+				{Type: libhive.EventTestEnded, Time: old, Test: 1, Result: &libhive.TestResult{Pass: true}},
+			}
+		)
+		var data []byte
+		for _, ev := range events {
+			line, _ := json.Marshal(&ev)
+			data = append(append(data, line...), '\n')
+		}
+		os.MkdirAll(filepath.Join(dir, libhive.EventsDir), 0755)
+		os.WriteFile(filepath.Join(dir, filepath.FromSlash(eventLog)), data, 0644)
+		// Files of the running run are old, but referenced by its event log.
+		for _, f := range []string{"running-sim.log", "geth/running.log", "old-orphan.log"} {
+			file := filepath.Join(dir, filepath.FromSlash(f))
+			os.WriteFile(file, []byte("log"), 0644)
+			os.Chtimes(file, old, old)
+		}
+		// Recent files which don't belong to a suite are kept.
+		os.WriteFile(filepath.Join(dir, "recent-orphan.log"), []byte("log"), 0644)
+
+		_, err := logdirGC(dir, gcConfig{cutoff: now.Add(-time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKept(t, dir, logs, []bool{false, false, false, false, false})
+		for f, want := range map[string]bool{
+			eventLog:            true,
+			"running-sim.log":   true,
+			"geth/running.log":  true,
+			"recent-orphan.log": true,
+			"old-orphan.log":    false,
+		} {
+			if exists(dir, f) != want {
+				t.Errorf("%s: exists=%t, want %t", f, !want, want)
+			}
+		}
+	})
+
+	t.Run("compress", func(t *testing.T) {
+		dir, logs := writeGCTestDir(t, now, suites)
+		report, err := logdirGC(dir, gcConfig{cutoff: now.Add(-100 * day), compressBefore: now.Add(-10 * day)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, log := range logs {
+			compressed := i < 4
+			if exists(dir, log) == compressed || exists(dir, log+".gz") != compressed {
+				t.Errorf("client log %d: wrong compression state", i)
+			}
+		}
+		if report.compressedFiles != 4 || report.compressedBytes <= 0 || report.deletedFiles != 0 {
+			t.Fatalf("wrong report: %+v", report)
+		}
+
+		// Compressed logs should still be served.
+		gfs := newGzipFallbackFS(os.DirFS(dir))
+		content, err := gfs.Open(logs[0])
+		if err != nil {
+			t.Fatal("can't open compressed log:", err)
+		}
+		defer content.Close()
+		stat, _ := content.Stat()
+		if stat.Size() != 1000 {
+			t.Fatalf("wrong size of decompressed log: %d", stat.Size())
+		}
+		if stat.Name() != path.Base(logs[0]) {
+			t.Fatalf("wrong name of decompressed log: %q", stat.Name())
+		}
+
+		// The decompressed file should be cached.
+		again, err := gfs.Open(logs[0])
+		if err != nil {
+			t.Fatal("can't reopen compressed log:", err)
+		}
+		defer again.Close()
+		if again.(*os.File).Name() != content.(*os.File).Name() {
+			t.Fatalf("compressed log was not served from cache")
+		}
+
+		// Closing the cache removes the decompressed files.
+		cacheDir := gfs.cache.dir
+		gfs.cache.close()
+		if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+			t.Fatalf("cache directory not removed: %v", err)
+		}
+	})
+}
+
+func checkKept(t *testing.T, dir string, logs []string, want []bool) {
+	t.Helper()
+	for i, log := range logs {
+		if exists(dir, log) != want[i] {
+			t.Errorf("client log %d: exists=%t, want %t", i, !want[i], want[i])
+		}
+	}
+}
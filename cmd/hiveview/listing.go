@@ -16,23 +16,16 @@ import (
 
 const listLimit = 200 // number of runs reported
 
+// suiteIndex is implemented by result stores which keep an index of suite metadata.
+type suiteIndex interface {
+	// listingEntries returns the latest suites, ordered by file name high->low.
+	listingEntries(limit int) ([]listingEntry, error)
+}
+
 // generateListing processes hive simulation output files and generates a listing file.
 func generateListing(fsys fs.FS, dir string, output io.Writer) error {
-	var (
-		stop    = errors.New("stop")
-		entries []listingEntry
-	)
-	// The files are walked in name order high->low. So to get the latest 200 items, we
-	// just need to keep going until we have 200.
-	err := walkSummaryFiles(fsys, dir, func(suite *libhive.TestSuite, fi fs.FileInfo) error {
-		entry := suiteToEntry(suite, fi)
-		entries = append(entries, entry)
-		if len(entries) >= listLimit {
-			return stop
-		}
-		return nil
-	})
-	if err != nil && err != stop {
+	entries, err := readListing(fsys, dir)
+	if err != nil {
 		return err
 	}
 
@@ -52,6 +45,37 @@ func generateListing(fsys fs.FS, dir string, output io.Writer) error {
 	return nil
 }
 
+// readListing returns the latest suites in dir, ordered newest-first.
+// The suite index is used when the store has one.
+func readListing(fsys fs.FS, dir string) ([]listingEntry, error) {
+	if index, ok := fsys.(suiteIndex); ok && dir == "." {
+		return index.listingEntries(listLimit)
+	}
+	return collectListing(fsys, dir)
+}
+
+// collectListing parses the latest suite files in dir.
+func collectListing(fsys fs.FS, dir string) ([]listingEntry, error) {
+	var (
+		stop    = errors.New("stop")
+		entries []listingEntry
+	)
+	// The files are walked in name order high->low. So to get the latest 200 items, we
+	// just need to keep going until we have 200.
+	err := walkSummaryFiles(fsys, dir, func(suite *libhive.TestSuite, fi fs.FileInfo) error {
+		entry := suiteToEntry(suite, fi)
+		entries = append(entries, entry)
+		if len(entries) >= listLimit {
+			return stop
+		}
+		return nil
+	})
+	if err != nil && err != stop {
+		return nil, err
+	}
+	return entries, nil
+}
+
 type listingEntry struct {
 	// Test suite information.
 	Name   string `json:"name"`
@@ -59,6 +83,7 @@ type listingEntry struct {
 	// Info about this run.
 	Passes   int       `json:"passes"`
 	Fails    int       `json:"fails"`
+	Skips    int       `json:"skips"`
 	Timeout  bool      `json:"timeout"`
 	Clients  []string  `json:"clients"`  // client names involved in this run
 	Start    time.Time `json:"start"`    // timestamp of test start (ISO 8601 format)
@@ -77,9 +102,12 @@ func suiteToEntry(s *libhive.TestSuite, file fs.FileInfo) listingEntry {
 	}
 	for _, test := range s.TestCases {
 		e.NTests++
-		if test.SummaryResult.Pass {
+		switch {
+		case test.SummaryResult.Skipped:
+			e.Skips++
+		case test.SummaryResult.Pass:
 			e.Passes++
-		} else {
+		default:
 			e.Fails++
 		}
 		if test.SummaryResult.Timeout {
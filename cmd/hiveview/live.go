@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+// liveRunTimeout is the time after which a run is considered dead
+// when no events have been written to its event log.
+const liveRunTimeout = 24 * time.Hour
+
+// liveRun is the state of an in-progress simulation run.
+type liveRun struct {
+	File    string       `json:"file"` // event log file
+	Sim     string       `json:"sim"`
+	SimLog  string       `json:"simLog"`
+	Start   time.Time    `json:"start"`
+	Updated time.Time    `json:"updated"`
+	Suites  []*liveSuite `json:"suites"`
+
+	ended bool
+	files []string // log files written by the run so far
+}
+
+type liveSuite struct {
+	ID      libhive.TestSuiteID `json:"id"`
+	Name    string              `json:"name"`
+	Start   time.Time           `json:"start"`
+	Ended   bool                `json:"ended"`
+	Passes  int                 `json:"passes"`
+	Fails   int                 `json:"fails"`
+	Skips   int                 `json:"skips"`
+	Running []*liveTest         `json:"running"`
+}
+
+type liveTest struct {
+	ID      libhive.TestID        `json:"id"`
+	Name    string                `json:"name"`
+	Start   time.Time             `json:"start"`
+	Clients []*libhive.ClientInfo `json:"clients"`
+}
+
+// readLiveRuns reads all event logs and returns the runs which are still in progress.
+func readLiveRuns(fsys fs.FS, now time.Time) ([]*liveRun, error) {
+	entries, err := fs.ReadDir(fsys, libhive.EventsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []*liveRun{}, nil
+		}
+		return nil, err
+	}
+	runs := make([]*liveRun, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		if info, err := entry.Info(); err != nil || now.Sub(info.ModTime()) > liveRunTimeout {
+			continue
+		}
+		name := path.Join(libhive.EventsDir, entry.Name())
+		run, err := readLiveRun(fsys, name)
+		if err != nil {
+			log.Printf("Can't read event log %s: %v", name, err)
+			continue
+		}
+		if !run.ended {
+			runs = append(runs, run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Start.After(runs[j].Start)
+	})
+	return runs, nil
+}
+
+// readLiveRun replays the events of an event log.
+func readLiveRun(fsys fs.FS, name string) (*liveRun, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		run    = &liveRun{File: name, Suites: make([]*liveSuite, 0)}
+		suites = make(map[libhive.TestSuiteID]*liveSuite)
+		dec    = json.NewDecoder(f)
+	)
+	for {
+		var ev libhive.Event
+		if err := dec.Decode(&ev); err != nil {
+			// Stop at EOF. Note the last line may also be incomplete
+			// while hive is writing it.
+			break
+		}
+		run.Updated = ev.Time
+		suite := suites[ev.Suite]
+
+		switch ev.Type {
+		case libhive.EventSimStarted:
+			run.Sim = ev.Name
+			run.SimLog = ev.SimLog
+			run.Start = ev.Time
+			if ev.SimLog != "" {
+				run.files = append(run.files, ev.SimLog)
+			}
+		case libhive.EventSimEnded:
+			run.ended = true
+		case libhive.EventSuiteStarted:
+			suite = &liveSuite{ID: ev.Suite, Name: ev.Name, Start: ev.Time, Running: make([]*liveTest, 0)}
+			suites[ev.Suite] = suite
+			run.Suites = append(run.Suites, suite)
+		case libhive.EventSuiteEnded:
+			if suite != nil {
+				suite.Ended = true
+			}
+		case libhive.EventTestStarted:
+			if suite != nil {
+				test := &liveTest{ID: ev.Test, Name: ev.Name, Start: ev.Time, Clients: make([]*libhive.ClientInfo, 0)}
+				suite.Running = append(suite.Running, test)
+			}
+		case libhive.EventTestEnded:
+			if suite != nil {
+				suite.removeTest(ev.Test)
+				switch {
+				case ev.Result != nil && ev.Result.Skipped:
+					suite.Skips++
+				case ev.Result != nil && ev.Result.Pass:
+					suite.Passes++
+				default:
+					suite.Fails++
+				}
+			}
+		case libhive.EventClientStarted:
+			if ev.Client != nil && ev.Client.LogFile != "" {
+				run.files = append(run.files, ev.Client.LogFile)
+			}
+			if suite != nil && ev.Client != nil {
+				if test := suite.test(ev.Test); test != nil {
+					test.Clients = append(test.Clients, ev.Client)
+				}
+			}
+		}
+	}
+	return run, nil
+}
+
+func (s *liveSuite) test(id libhive.TestID) *liveTest {
+	for _, t := range s.Running {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *liveSuite) removeTest(id libhive.TestID) {
+	for i, t := range s.Running {
+		if t.ID == id {
+			s.Running = append(s.Running[:i], s.Running[i+1:]...)
+			return
+		}
+	}
+}
+
+type serveLive struct{ fsys fs.FS }
+
+func (h serveLive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	runs, err := readLiveRuns(h.fsys, time.Now())
+	if err != nil {
+		log.Printf("Can't read live runs: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("cache-control", "no-cache")
+	json.NewEncoder(w).Encode(runs)
+}
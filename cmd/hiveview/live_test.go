@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+func TestReadLiveRuns(t *testing.T) {
+	var (
+		now   = time.Now()
+		start = now.Add(-time.Hour)
+	)
+	eventLog := func(events ...libhive.Event) *fstest.MapFile {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, ev := range events {
+			ev.Time = start
+			enc.Encode(&ev)
+		}
+		return &fstest.MapFile{Data: buf.Bytes(), ModTime: now}
+	}
+	fsys := fstest.MapFS{
+		"live/1-running.jsonl": eventLog(
+			libhive.Event{Type: libhive.EventSimStarted, Name: "sim", SimLog: "sim.log"},
+			libhive.Event{Type: libhive.EventSuiteStarted, Suite: 0, Name: "suite"},
+			libhive.Event{Type: libhive.EventTestStarted, Suite: 0, Test: 1, Name: "test 1"},
+			libhive.Event{Type: libhive.EventTestStarted, Suite: 0, Test: 2, Name: "test 2"},
+			libhive.Event{Type: libhive.EventClientStarted, Suite: 0, Test: 2, Client: &libhive.ClientInfo{ID: "c1", Name: "client"}},
+			libhive.Event{Type: libhive.EventTestEnded, Suite: 0, Test: 1, Result: &libhive.TestResult{Pass: true}},
+		),
+		"live/2-ended.jsonl": eventLog(
+			libhive.Event{Type: libhive.EventSimStarted, Name: "sim2"},
+			libhive.Event{Type: libhive.EventSimEnded, Name: "sim2"},
+		),
+	}
+
+	runs, err := readLiveRuns(fsys, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("wrong number of runs: %d", len(runs))
+	}
+	run := runs[0]
+	if run.Sim != "sim" || run.SimLog != "sim.log" || len(run.Suites) != 1 {
+		t.Fatalf("wrong run: %+v", run)
+	}
+	suite := run.Suites[0]
+	if suite.Passes != 1 || suite.Fails != 0 || len(suite.Running) != 1 {
+		t.Fatalf("wrong suite state: %+v", suite)
+	}
+	test := suite.Running[0]
+	if test.Name != "test 2" || len(test.Clients) != 1 || test.Clients[0].ID != "c1" {
+		t.Fatalf("wrong running test: %+v", test)
+	}
+
+	// Runs without recent events are not reported.
+	runs, _ = readLiveRuns(fsys, now.Add(2*liveRunTimeout))
+	if len(runs) != 0 {
+		t.Fatalf("stale run reported")
+	}
+}
@@ -22,13 +22,20 @@ func main() {
 		serve          = flag.Bool("serve", false, "Enables the HTTP server")
 		listing        = flag.Bool("listing", false, "Generates listing JSON to stdout")
 		deploy         = flag.Bool("deploy", false, "Compiles the frontend to a static directory")
+		importDir      = flag.String("import", "", "Imports the results `directory` into the results store")
 		gc             = flag.Bool("gc", false, "Deletes old log files")
 		gcKeepInterval = flag.Duration("keep", 5*durationMonth, "Time interval of past log files to keep (for -gc)")
 		gcKeepMin      = flag.Int("keep-min", 10, "Minmum number of suite outputs to keep (for -gc)")
+		gcKeepRuns     = flag.Int("keep-runs", 0, "Number of recent runs to keep per suite and client combination (for -gc)")
+		gcKeepFailing  = flag.Duration("keep-failing", 0, "Time interval of past failing suite outputs to keep (for -gc)")
+		gcQuota        = flag.String("quota", "", "Maximum total `size` of kept log files, e.g. 50GB (for -gc)")
+		gcCompress     = flag.Duration("compress-after", 0, "Compresses client logs older than the given `duration` (for -gc)")
+		gcDryRun       = flag.Bool("dry-run", false, "Only reports the files that would be deleted (for -gc)")
 		config         serverConfig
 	)
 	flag.StringVar(&config.listenAddr, "addr", "0.0.0.0:8080", "HTTP server listen address")
 	flag.StringVar(&config.logDir, "logdir", "workspace/logs", "Path to hive simulator log directory")
+	flag.StringVar(&config.store, "store", "", "Results store `location` (dir:path, s3://bucket/prefix?endpoint=URL, sqlite:file). Uses -logdir when not set.")
 	flag.StringVar(&config.assetsDir, "assets", "", "Path to static files directory. Serves baked-in assets when not set.")
 	flag.BoolVar(&config.disableBundle, "assets.nobundle", false, "Disables JS/CSS bundling (for development).")
 	flag.Parse()
@@ -38,11 +45,45 @@ func main() {
 	case *serve:
 		runServer(config)
 	case *listing:
-		fsys := os.DirFS(config.logDir)
-		generateListing(fsys, ".", os.Stdout)
+		store, err := config.openStore()
+		if err != nil {
+			log.Fatalf("-store: %v", err)
+		}
+		defer store.Close()
+		generateListing(store, ".", os.Stdout)
+	case *importDir != "":
+		doImport(&config, *importDir)
 	case *gc:
-		cutoff := time.Now().Add(-*gcKeepInterval)
-		logdirGC(config.logDir, cutoff, *gcKeepMin)
+		now := time.Now()
+		cfg := gcConfig{
+			cutoff:   now.Add(-*gcKeepInterval),
+			keepMin:  *gcKeepMin,
+			keepRuns: *gcKeepRuns,
+			dryRun:   *gcDryRun,
+		}
+		if *gcKeepFailing > 0 {
+			cfg.keepFailing = now.Add(-*gcKeepFailing)
+		}
+		if *gcCompress > 0 {
+			cfg.compressBefore = now.Add(-*gcCompress)
+		}
+		if *gcQuota != "" {
+			quota, err := parseSize(*gcQuota)
+			if err != nil {
+				log.Fatalf("-quota: %v", err)
+			}
+			cfg.quota = quota
+		}
+		// Garbage collection works on the files of the results directory.
+		dir, err := storeDir(config.store, config.logDir)
+		if err != nil {
+			log.Fatalf("-gc: %v", err)
+		}
+		report, err := logdirGC(dir, cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		report.print(os.Stdout, cfg.dryRun)
 	case *deploy:
 		doDeploy(&config)
 	default:
@@ -50,6 +91,24 @@ func main() {
 	}
 }
 
+// doImport copies a results directory into the store.
+func doImport(config *serverConfig, dir string) {
+	if config.store == "" {
+		log.Fatalf("-import requires -store")
+	}
+	store, err := config.openStore()
+	if err != nil {
+		log.Fatalf("-store: %v", err)
+	}
+	defer store.Close()
+
+	n, err := importResults(store, dir)
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+	log.Printf("imported %d files", n)
+}
+
 // doDeploy writes the UI to a directory.
 func doDeploy(config *serverConfig) {
 	if flag.NArg() != 1 {
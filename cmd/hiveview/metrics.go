@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	suiteRunsDesc = prometheus.NewDesc(
+		"hiveview_suite_runs",
+		"Number of suite runs in the listing.",
+		[]string{"suite", "client"}, nil,
+	)
+	suiteTestsDesc = prometheus.NewDesc(
+		"hiveview_suite_tests",
+		"Number of tests in the listed suite runs, excluding skipped tests.",
+		[]string{"suite", "client"}, nil,
+	)
+	suitePassesDesc = prometheus.NewDesc(
+		"hiveview_suite_passes",
+		"Number of passed tests in the listed suite runs.",
+		[]string{"suite", "client"}, nil,
+	)
+	suitePassRateDesc = prometheus.NewDesc(
+		"hiveview_suite_pass_rate",
+		"Ratio of passed tests in the listed suite runs.",
+		[]string{"suite", "client"}, nil,
+	)
+	suiteLatestPassRateDesc = prometheus.NewDesc(
+		"hiveview_suite_latest_pass_rate",
+		"Ratio of passed tests in the latest suite run.",
+		[]string{"suite", "client"}, nil,
+	)
+)
+
+// listingCollector computes aggregate pass rates per suite and client
+// from the listing of recent runs.
+type listingCollector struct{ fsys fs.FS }
+
+func newMetricsHandler(fsys fs.FS) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(listingCollector{fsys})
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+func (c listingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- suiteRunsDesc
+	ch <- suiteTestsDesc
+	ch <- suitePassesDesc
+	ch <- suitePassRateDesc
+	ch <- suiteLatestPassRateDesc
+}
+
+type suiteClientKey struct{ suite, client string }
+
+type suiteClientStats struct {
+	runs, tests, passes int // tests does not include skipped tests
+	latest              *listingEntry
+}
+
+func (c listingCollector) Collect(ch chan<- prometheus.Metric) {
+	entries, err := readListing(c.fsys, ".")
+	if err != nil {
+		log.Printf("Can't generate listing for metrics: %v", err)
+		return
+	}
+
+	// Aggregate. Note entries are ordered newest-first.
+	stats := make(map[suiteClientKey]*suiteClientStats)
+	for i := range entries {
+		e := &entries[i]
+		for _, client := range e.Clients {
+			key := suiteClientKey{e.Name, client}
+			s := stats[key]
+			if s == nil {
+				s = &suiteClientStats{latest: e}
+				stats[key] = s
+			}
+			s.runs++
+			s.tests += e.NTests - e.Skips
+			s.passes += e.Passes
+		}
+	}
+
+	for key, s := range stats {
+		ch <- prometheus.MustNewConstMetric(suiteRunsDesc, prometheus.GaugeValue, float64(s.runs), key.suite, key.client)
+		ch <- prometheus.MustNewConstMetric(suiteTestsDesc, prometheus.GaugeValue, float64(s.tests), key.suite, key.client)
+		ch <- prometheus.MustNewConstMetric(suitePassesDesc, prometheus.GaugeValue, float64(s.passes), key.suite, key.client)
+		ch <- prometheus.MustNewConstMetric(suitePassRateDesc, prometheus.GaugeValue, passRate(s.passes, s.tests), key.suite, key.client)
+		ch <- prometheus.MustNewConstMetric(suiteLatestPassRateDesc, prometheus.GaugeValue, passRate(s.latest.Passes, s.latest.NTests-s.latest.Skips), key.suite, key.client)
+	}
+}
+
+func passRate(passes, tests int) float64 {
+	if tests == 0 {
+		return 0
+	}
+	return float64(passes) / float64(tests)
+}
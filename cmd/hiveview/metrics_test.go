@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	dir := writeTestResults(t)
+	handler := newMetricsHandler(os.DirFS(dir))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	want := []string{
+		`hiveview_suite_runs{client="go-ethereum",suite="my-suite"} 1`,
+		`hiveview_suite_tests{client="go-ethereum",suite="my-suite"} 2`,
+		`hiveview_suite_pass_rate{client="go-ethereum",suite="my-suite"} 0.5`,
+	}
+	for _, line := range want {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("metric missing in output: %s", line)
+		}
+	}
+	if t.Failed() {
+		t.Log("output:\n", string(body))
+	}
+}
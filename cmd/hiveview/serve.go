@@ -1,14 +1,24 @@
 package main
 
 import (
+	"compress/gzip"
 	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -19,6 +29,7 @@ var embeddedAssets embed.FS
 type serverConfig struct {
 	listenAddr    string
 	logDir        string
+	store         string
 	assetsDir     string
 	disableBundle bool
 }
@@ -37,6 +48,10 @@ func (cfg *serverConfig) assetFS() (fs.FS, error) {
 	return sub, nil
 }
 
+func (cfg *serverConfig) openStore() (resultStore, error) {
+	return openStore(cfg.store, cfg.logDir)
+}
+
 func (cfg *serverConfig) useEmbeddedAssets() bool {
 	return cfg.assetsDir == ""
 }
@@ -46,15 +61,23 @@ func runServer(config serverConfig) {
 	if err != nil {
 		log.Fatalf("-assets: %v", err)
 	}
+	store, err := config.openStore()
+	if err != nil {
+		log.Fatalf("-store: %v", err)
+	}
+	defer store.Close()
 
 	// Create handlers.
 	deployFS := newDeployFS(assetFS, &config)
-	logDirFS := os.DirFS(config.logDir)
-	logHandler := http.FileServer(http.FS(logDirFS))
-	listingHandler := serveListing{fsys: logDirFS}
+	gzipFS := newGzipFallbackFS(store)
+	defer gzipFS.cache.close()
+	logHandler := http.FileServer(http.FS(gzipFS))
+	listingHandler := serveListing{fsys: store}
 
 	mux := mux.NewRouter()
 	mux.Handle("/listing.jsonl", listingHandler).Methods("GET")
+	mux.Handle("/live.json", serveLive{fsys: store}).Methods("GET")
+	mux.Handle("/metrics", newMetricsHandler(store)).Methods("GET")
 	mux.PathPrefix("/results").Handler(http.StripPrefix("/results/", logHandler))
 	mux.PathPrefix("/").Handler(serveFiles{deployFS})
 
@@ -64,7 +87,18 @@ func runServer(config serverConfig) {
 		log.Fatalf("Can't listen: %v", err)
 	}
 	log.Printf("Serving at http://%v/", l.Addr())
-	http.Serve(l, mux)
+
+	// Serve until interrupted, so that the deferred cleanup runs on shutdown.
+	srv := &http.Server{Handler: mux}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		srv.Close()
+	}()
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Server error: %v", err)
+	}
 }
 
 type serveListing struct{ fsys fs.FS }
@@ -78,6 +112,148 @@ func (h serveListing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// gzipFallbackFS serves files compressed by the log garbage collector.
+// When a file does not exist, but a .gz file with the same name exists,
+// the compressed file is decompressed and served instead.
+//
+// Decompressed files are cached on disk, so that the range requests made by the
+// log viewer do not decompress the whole file again.
+type gzipFallbackFS struct {
+	fs.FS
+	cache *gzipCache
+}
+
+func newGzipFallbackFS(fsys fs.FS) gzipFallbackFS {
+	return gzipFallbackFS{fsys, &gzipCache{limit: gzipCacheLimit, files: make(map[string]*gzipCacheEntry)}}
+}
+
+func (gfs gzipFallbackFS) Open(name string) (fs.File, error) {
+	f, err := gfs.FS.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return f, err
+	}
+	stat, gzerr := fs.Stat(gfs.FS, name+".gz")
+	if gzerr != nil {
+		return nil, err
+	}
+	file, gzerr := gfs.cache.get(gfs.FS, name, stat.ModTime())
+	if gzerr != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: gzerr}
+	}
+	return os.Open(file)
+}
+
+// gzipCacheLimit is the number of decompressed files kept by gzipFallbackFS.
+const gzipCacheLimit = 32
+
+// gzipCache holds decompressed files in a temporary directory.
+type gzipCache struct {
+	limit int
+	mu    sync.Mutex
+	dir   string
+	seq   int
+	files map[string]*gzipCacheEntry
+	lru   []string // names in files, least recently used first
+}
+
+type gzipCacheEntry struct {
+	once    sync.Once
+	modTime time.Time
+	file    string
+	err     error
+}
+
+// get returns the path of the decompressed file name. The file is decompressed
+// when it is not cached, or when the compressed file was modified.
+func (c *gzipCache) get(fsys fs.FS, name string, modTime time.Time) (string, error) {
+	c.mu.Lock()
+	if c.dir == "" {
+		dir, err := os.MkdirTemp("", "hiveview-gzip-")
+		if err != nil {
+			c.mu.Unlock()
+			return "", err
+		}
+		c.dir = dir
+	}
+	e := c.files[name]
+	if e == nil || !e.modTime.Equal(modTime) {
+		if e != nil {
+			os.RemoveAll(filepath.Dir(e.file))
+		}
+		c.seq++
+		e = &gzipCacheEntry{modTime: modTime, file: filepath.Join(c.dir, strconv.Itoa(c.seq), path.Base(name))}
+		c.files[name] = e
+	}
+	c.touch(name)
+	c.mu.Unlock()
+
+	e.once.Do(func() { e.err = decompressFile(fsys, name+".gz", e.file, modTime) })
+	return e.file, e.err
+}
+
+// close removes the temporary directory of the cache.
+func (c *gzipCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dir != "" {
+		os.RemoveAll(c.dir)
+		c.dir = ""
+		c.files = make(map[string]*gzipCacheEntry)
+		c.lru = nil
+	}
+}
+
+// touch marks name as most recently used, and evicts the least recently used files
+// when the cache is full. It must be called with c.mu held.
+func (c *gzipCache) touch(name string) {
+	for i, n := range c.lru {
+		if n == name {
+			c.lru = append(c.lru[:i], c.lru[i+1:]...)
+			break
+		}
+	}
+	c.lru = append(c.lru, name)
+	for len(c.lru) > c.limit {
+		evicted := c.lru[0]
+		c.lru = c.lru[1:]
+		// Open files remain readable after removal.
+		os.RemoveAll(filepath.Dir(c.files[evicted].file))
+		delete(c.files, evicted)
+	}
+}
+
+// decompressFile writes the decompressed content of the gzip file src to dst.
+func decompressFile(fsys fs.FS, src, dst string, modTime time.Time) error {
+	gzf, err := fsys.Open(src)
+	if err != nil {
+		return err
+	}
+	defer gzf.Close()
+	r, err := gzip.NewReader(gzf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	os.Chtimes(tmp, modTime, modTime)
+	return os.Rename(tmp, dst)
+}
+
 type serveFiles struct{ fsys fs.FS }
 
 func (h serveFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// resultStore is a storage backend for hive results. The store is accessed through
+// the fs.FS interface when serving results and generating the listing. Names are
+// slash-separated paths relative to the root of the results directory, i.e. the
+// paths referenced by the suite files.
+type resultStore interface {
+	fs.ReadDirFS
+
+	// Put stores a file, replacing any existing content.
+	Put(name string, content io.Reader) error
+
+	// Close releases resources held by the store.
+	Close() error
+}
+
+// openStore opens the results store described by spec. Supported formats are:
+//
+//   - "" or a plain directory path (uses the local directory)
+//   - "dir:path" (local directory)
+//   - "s3://bucket/prefix?endpoint=http://host:port&region=us-east-1" (S3-compatible bucket)
+//   - "sqlite:file.db" (SQLite database)
+//
+// When spec is empty, the local directory logDir is used.
+func openStore(spec, logDir string) (resultStore, error) {
+	switch {
+	case spec == "":
+		return newDirStore(logDir), nil
+	case strings.HasPrefix(spec, "dir:"):
+		return newDirStore(strings.TrimPrefix(spec, "dir:")), nil
+	case strings.HasPrefix(spec, "s3://"):
+		u, err := url.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 URL: %v", err)
+		}
+		return newS3Store(u)
+	case strings.HasPrefix(spec, "sqlite:"):
+		return newSQLiteStore(strings.TrimPrefix(spec, "sqlite:"))
+	case strings.Contains(spec, "://"):
+		return nil, fmt.Errorf("unsupported store URL %q", spec)
+	default:
+		return newDirStore(spec), nil
+	}
+}
+
+// storeDir returns the local directory of the results store described by spec.
+// It fails for stores which are not backed by a local directory.
+func storeDir(spec, logDir string) (string, error) {
+	switch {
+	case spec == "":
+		return logDir, nil
+	case strings.HasPrefix(spec, "dir:"):
+		return strings.TrimPrefix(spec, "dir:"), nil
+	case strings.HasPrefix(spec, "sqlite:") || strings.Contains(spec, "://"):
+		return "", fmt.Errorf("store %q is not a local directory", spec)
+	default:
+		return spec, nil
+	}
+}
+
+// importResults copies all files in the results directory dir into the store.
+func importResults(store resultStore, dir string) (files int, err error) {
+	fsys := os.DirFS(dir)
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := store.Put(name, f); err != nil {
+			return fmt.Errorf("can't store %s: %v", name, err)
+		}
+		log.Println("import", name)
+		files++
+		return nil
+	})
+	return files, err
+}
+
+// dirStore is a resultStore backed by a local directory.
+type dirStore struct {
+	fs.FS
+	dir string
+}
+
+func newDirStore(dir string) *dirStore {
+	return &dirStore{FS: os.DirFS(dir), dir: dir}
+}
+
+func (s *dirStore) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.FS, name)
+}
+
+func (s *dirStore) Put(name string, content io.Reader) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "put", Path: name, Err: fs.ErrInvalid}
+	}
+	file := filepath.Join(s.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+func (s *dirStore) Close() error {
+	return nil
+}
+
+// fileInfo is a static fs.FileInfo/fs.DirEntry, used by stores that
+// are not backed by a file system.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (fi *fileInfo) Name() string               { return fi.name }
+func (fi *fileInfo) Size() int64                { return fi.size }
+func (fi *fileInfo) ModTime() time.Time         { return fi.modTime }
+func (fi *fileInfo) IsDir() bool                { return fi.dir }
+func (fi *fileInfo) Sys() any                   { return nil }
+func (fi *fileInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi *fileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// dirFile is a virtual directory in a store.
+type dirFile struct {
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func newDirFile(name string, entries []fs.DirEntry) *dirFile {
+	return &dirFile{info: fileInfo{name: path.Base(name), dir: true}, entries: entries}
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return &d.info, nil }
+func (d *dirFile) Close() error               { return nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
+
+// dirEntries computes the entries of directory dir from a flat list of files.
+// Subdirectories are inferred from the file names.
+func dirEntries(dir string, files []fileInfo) []fs.DirEntry {
+	prefix := ""
+	if dir != "." {
+		prefix = dir + "/"
+	}
+	var (
+		entries []fs.DirEntry
+		subdirs = make(map[string]bool)
+	)
+	for i := range files {
+		rel := strings.TrimPrefix(files[i].name, prefix)
+		if rel == files[i].name && prefix != "" {
+			continue
+		}
+		if slash := strings.IndexByte(rel, '/'); slash >= 0 {
+			subdir := rel[:slash]
+			if !subdirs[subdir] {
+				subdirs[subdir] = true
+				entries = append(entries, &fileInfo{name: subdir, dir: true})
+			}
+			continue
+		}
+		fi := files[i]
+		fi.name = rel
+		entries = append(entries, &fi)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS files (
+	name    TEXT PRIMARY KEY,
+	content BLOB NOT NULL,
+	modtime INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS suites (
+	file    TEXT PRIMARY KEY,
+	name    TEXT NOT NULL,
+	ntests  INTEGER NOT NULL,
+	passes  INTEGER NOT NULL,
+	fails   INTEGER NOT NULL,
+	skips   INTEGER NOT NULL,
+	timeout INTEGER NOT NULL,
+	clients TEXT NOT NULL,
+	start   INTEGER NOT NULL,
+	size    INTEGER NOT NULL,
+	simlog  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS suites_by_name ON suites (name, start);
+`
+
+// sqliteStore is a resultStore backed by an SQLite database.
+//
+// All result files are stored in the database. In addition, the metadata of each suite
+// is kept in a separate table, which allows generating the listing without parsing
+// the suite files.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(file string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+// Open reads a file from the database.
+func (s *sqliteStore) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	var (
+		content []byte
+		modtime int64
+	)
+	row := s.db.QueryRow(`SELECT content, modtime FROM files WHERE name = ?`, name)
+	err := row.Scan(&content, &modtime)
+	switch {
+	case err == nil:
+		return newMemFile(path.Base(name), time.Unix(modtime, 0), content), nil
+	case errors.Is(err, sql.ErrNoRows):
+		entries, err := s.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 && name != "." {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return newDirFile(name, entries), nil
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+}
+
+// ReadDir lists a directory.
+func (s *sqliteStore) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	rows, err := s.db.Query(`SELECT name, length(content), modtime FROM files WHERE substr(name, 1, ?) = ?`, len(prefix), prefix)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	defer rows.Close()
+
+	var files []fileInfo
+	for rows.Next() {
+		var (
+			fi      fileInfo
+			modtime int64
+		)
+		if err := rows.Scan(&fi.name, &fi.size, &modtime); err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+		}
+		fi.modTime = time.Unix(modtime, 0)
+		files = append(files, fi)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return dirEntries(name, files), nil
+}
+
+// Put stores a file. When the file is a suite file in the root directory,
+// its metadata is also added to the index.
+func (s *sqliteStore) Put(name string, content io.Reader) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "put", Path: name, Err: fs.ErrInvalid}
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	modtime := time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO files (name, content, modtime) VALUES (?, ?, ?)`, name, data, modtime.Unix())
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(name, "/") && strings.HasSuffix(name, ".json") && !skipFile(name) {
+		var suite libhive.TestSuite
+		if json.NewDecoder(bytes.NewReader(data)).Decode(&suite) == nil && suiteValid(&suite) {
+			info := &fileInfo{name: name, size: int64(len(data)), modTime: modtime}
+			if err := insertSuite(tx, suiteToEntry(&suite, info)); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func insertSuite(tx *sql.Tx, e listingEntry) error {
+	var start int64
+	if !e.Start.IsZero() {
+		start = e.Start.UnixNano()
+	}
+	clients, _ := json.Marshal(e.Clients)
+	_, err := tx.Exec(`INSERT OR REPLACE INTO suites
+		(file, name, ntests, passes, fails, skips, timeout, clients, start, size, simlog)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.FileName, e.Name, e.NTests, e.Passes, e.Fails, e.Skips, e.Timeout, string(clients), start, e.Size, e.SimLog)
+	return err
+}
+
+// listingEntries returns the latest suites from the index.
+func (s *sqliteStore) listingEntries(limit int) ([]listingEntry, error) {
+	rows, err := s.db.Query(`SELECT file, name, ntests, passes, fails, skips, timeout, clients, start, size, simlog
+		FROM suites ORDER BY file DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []listingEntry
+	for rows.Next() {
+		var (
+			e       listingEntry
+			clients string
+			start   int64
+		)
+		err := rows.Scan(&e.FileName, &e.Name, &e.NTests, &e.Passes, &e.Fails, &e.Skips, &e.Timeout, &clients, &start, &e.Size, &e.SimLog)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(clients), &e.Clients); err != nil {
+			return nil, err
+		}
+		if start != 0 {
+			e.Start = time.Unix(0, start).UTC()
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+// writeTestResults creates a results directory containing a single suite.
+func writeTestResults(t *testing.T) string {
+	dir := t.TempDir()
+	suite := libhive.TestSuite{
+		Name:           "my-suite",
+		SimulatorLog:   "1700000000-simulator-abc.log",
+		TestDetailsLog: "details/1700000000-abc-0.log",
+		TestCases: map[libhive.TestID]*libhive.TestCase{
+			1: {
+				Name:          "test 1",
+				Start:         time.Unix(1700000000, 0).UTC(),
+				SummaryResult: libhive.TestResult{Pass: true},
+				ClientInfo: map[string]*libhive.ClientInfo{
+					"c1": {ID: "c1", Name: "go-ethereum", LogFile: "go-ethereum/client-c1.log"},
+				},
+			},
+			2: {
+				Name:          "test 2",
+				Start:         time.Unix(1700000001, 0).UTC(),
+				SummaryResult: libhive.TestResult{Pass: false},
+			},
+			3: {
+				Name:          "test 3",
+				Start:         time.Unix(1700000002, 0).UTC(),
+				SummaryResult: libhive.TestResult{Pass: true, Skipped: true},
+			},
+		},
+	}
+	suiteJSON, _ := json.Marshal(&suite)
+	files := map[string]string{
+		"1700000000-suite.json":        string(suiteJSON),
+		"1700000000-simulator-abc.log": "sim log",
+		"details/1700000000-abc-0.log": "details",
+		"go-ethereum/client-c1.log":    "client log",
+		"hive.json":                    "{}",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestStores(t *testing.T) {
+	s3srv := newFakeS3()
+	defer s3srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	tests := map[string]string{
+		"dir":    "dir:" + t.TempDir(),
+		"s3":     "s3://bucket/results?endpoint=" + url.QueryEscape(s3srv.URL),
+		"sqlite": "sqlite:" + filepath.Join(t.TempDir(), "results.db"),
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := openStore(spec, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			testStore(t, store)
+		})
+	}
+}
+
+func TestStoreDir(t *testing.T) {
+	tests := []struct {
+		spec, want string
+		err        bool
+	}{
+		{spec: "", want: "logdir"},
+		{spec: "dir:results", want: "results"},
+		{spec: "results", want: "results"},
+		{spec: "sqlite:results.db", err: true},
+		{spec: "s3://bucket/results", err: true},
+	}
+	for _, test := range tests {
+		dir, err := storeDir(test.spec, "logdir")
+		if (err != nil) != test.err || dir != test.want {
+			t.Errorf("storeDir(%q) = %q, %v", test.spec, dir, err)
+		}
+	}
+}
+
+func TestS3StoreRangeRead(t *testing.T) {
+	s3srv := newFakeS3()
+	defer s3srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	store, err := openStore("s3://bucket?endpoint="+url.QueryEscape(s3srv.URL), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("client.log", strings.NewReader("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Serve a range request and check only the requested part of the object
+	// is fetched from the bucket.
+	req := httptest.NewRequest("GET", "/client.log", nil)
+	req.Header.Set("range", "bytes=6-")
+	rec := httptest.NewRecorder()
+	serveFiles{store}.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "6789" {
+		t.Fatalf("wrong response %d %q", rec.Code, rec.Body.String())
+	}
+	s3srv.mu.Lock()
+	defer s3srv.mu.Unlock()
+	if len(s3srv.ranges) != 1 || s3srv.ranges[0] != "bytes=6-" {
+		t.Fatalf("wrong object requests: %q", s3srv.ranges)
+	}
+}
+
+func testStore(t *testing.T, store resultStore) {
+	n, err := importResults(store, writeTestResults(t))
+	if err != nil {
+		t.Fatal("import error:", err)
+	}
+	if n != 5 {
+		t.Fatalf("wrong number of imported files: %d", n)
+	}
+
+	// Check files can be read back.
+	content, err := fs.ReadFile(store, "go-ethereum/client-c1.log")
+	if err != nil {
+		t.Fatal("can't read client log:", err)
+	}
+	if string(content) != "client log" {
+		t.Fatalf("wrong client log content %q", content)
+	}
+	if _, err := fs.ReadFile(store, "missing.log"); !os.IsNotExist(err) {
+		t.Fatalf("wrong error for missing file: %v", err)
+	}
+
+	// Check files can be read from an offset, as done for range requests.
+	f, err := store.Open("go-ethereum/client-c1.log")
+	if err != nil {
+		t.Fatal("can't open client log:", err)
+	}
+	defer f.Close()
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		t.Fatalf("file %T is not seekable", f)
+	}
+	if _, err := rs.Seek(7, io.SeekStart); err != nil {
+		t.Fatal("seek error:", err)
+	}
+	if rest, err := io.ReadAll(rs); err != nil || string(rest) != "log" {
+		t.Fatalf("wrong content after seek %q (err %v)", rest, err)
+	}
+
+	// Check directory listing.
+	entries, err := store.ReadDir(".")
+	if err != nil {
+		t.Fatal("ReadDir error:", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"1700000000-simulator-abc.log", "1700000000-suite.json", "details", "go-ethereum", "hive.json"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("wrong root directory entries %v", names)
+	}
+
+	// Check the listing.
+	var buf bytes.Buffer
+	if err := generateListing(store, ".", &buf); err != nil {
+		t.Fatal("listing error:", err)
+	}
+	var entry listingEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal("invalid listing:", err)
+	}
+	if entry.Name != "my-suite" || entry.NTests != 3 || entry.Passes != 1 || entry.Fails != 1 || entry.Skips != 1 {
+		t.Fatalf("wrong listing entry %+v", entry)
+	}
+	if entry.FileName != "1700000000-suite.json" || !entry.Start.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("wrong listing entry %+v", entry)
+	}
+	if len(entry.Clients) != 1 || entry.Clients[0] != "go-ethereum" {
+		t.Fatalf("wrong clients in listing entry: %v", entry.Clients)
+	}
+}
+
+// fakeS3 is a minimal in-memory S3-compatible server.
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	objects map[string][]byte
+	ranges  []string // range headers of GET requests
+}
+
+func newFakeS3() *fakeS3 {
+	s := &fakeS3{objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key, _ := strings.Cut(path, "/")
+	if bucket != "bucket" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == "PUT":
+		s.objects[key], _ = io.ReadAll(r.Body)
+	case r.Method == "GET" && key == "":
+		s.serveList(w, r)
+	case r.Method == "GET" || r.Method == "HEAD":
+		content, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		if r.Method == "GET" {
+			s.ranges = append(s.ranges, r.Header.Get("range"))
+		}
+		http.ServeContent(w, r, key, time.Now().UTC(), bytes.NewReader(content))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeS3) serveList(w http.ResponseWriter, r *http.Request) {
+	var (
+		prefix    = r.URL.Query().Get("prefix")
+		delimiter = r.URL.Query().Get("delimiter")
+		result    s3ListResult
+		prefixes  = make(map[string]bool)
+	)
+	for key, content := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			prefixes[prefix+rest[:i+1]] = true
+			continue
+		}
+		result.Contents = append(result.Contents, struct {
+			Key          string
+			Size         int64
+			LastModified time.Time
+		}{key, int64(len(content)), time.Now()})
+	}
+	for p := range prefixes {
+		result.CommonPrefixes = append(result.CommonPrefixes, struct{ Prefix string }{p})
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	xml.NewEncoder(w).Encode(&result)
+}
@@ -2,17 +2,23 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ethereum/hive/internal/libdocker"
 	"github.com/ethereum/hive/internal/libhive"
+	"github.com/ethereum/hive/internal/notify"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -31,8 +37,13 @@ func main() {
 		simTestLimit          = flag.Int("sim.testlimit", 0, "[DEPRECATED] Max `number` of tests to execute per client (interpreted by simulators).")
 		simTimeLimit          = flag.Duration("sim.timelimit", 0, "Simulation `timeout`. Hive aborts the simulator if it exceeds this time.")
 		simLogLevel           = flag.Int("sim.loglevel", 3, "Selects log `level` of client instances. Supports values 0-5.")
+		simArtifactLimit      = flag.Int64("sim.artifactlimit", libhive.DefaultArtifactSizeLimit>>20, "Max total size of artifacts attached to a test, in `MiB`.")
+		listTests             = flag.Bool("list-tests", false, "Lists the tests selected by --sim.limit instead of running them.")
+		listFormat            = flag.String("list-tests.format", "text", "Output `format` of --list-tests, \"text\" or \"json\".")
 		simDevMode            = flag.Bool("dev", false, "Only starts the simulator API endpoint (listening at 127.0.0.1:3000 by default) without starting any simulators.")
 		simDevModeAPIEndpoint = flag.String("dev.addr", "127.0.0.1:3000", "Endpoint that the simulator API listens on")
+		metricsAddr           = flag.String("metrics.addr", "", "Serves Prometheus metrics on the given `address` (e.g. 127.0.0.1:9090). Disabled when empty.")
+		notifyFile            = flag.String("notify", "", "YAML `file` configuring notifications sent after each simulation run.")
 		useCredHelper         = flag.Bool("docker.cred-helper", false, "configure docker authentication using locally-configured credential helper")
 		overrideDockerfile    = flag.String("docker.override-dockerfile", "", "override the dockerfile used to build the client image")
 
@@ -70,6 +81,9 @@ func main() {
 	if *simPattern != "" && len(simList) == 0 {
 		fatal("no simulators for pattern", *simPattern)
 	}
+	if *listTests && *listFormat != "text" && *listFormat != "json" {
+		fatal("invalid --list-tests.format:", *listFormat)
+	}
 	if *simPattern != "" && *simDevMode {
 		log15.Warn("--sim is ignored when using --dev mode")
 		simList = nil
@@ -107,6 +121,22 @@ func main() {
 		cancel()
 	}()
 
+	// Load the notification config.
+	var notifier *notify.Dispatcher
+	if *notifyFile != "" {
+		notifier, err = notify.LoadConfig(*notifyFile)
+		if err != nil {
+			fatal("-notify:", err)
+		}
+	}
+
+	// Start the metrics server.
+	if *metricsAddr != "" {
+		if err := startMetricsServer(*metricsAddr); err != nil {
+			fatal("-metrics.addr:", err)
+		}
+	}
+
 	// Run.
 	env := libhive.SimEnv{
 		LogDir:             *testResultsRoot,
@@ -116,6 +146,7 @@ func main() {
 		SimRandomSeed:      *simRandomSeed,
 		SimDurationLimit:   *simTimeLimit,
 		ClientStartTimeout: *clientTimeout,
+		ArtifactSizeLimit:  *simArtifactLimit << 20,
 	}
 	runner := libhive.NewRunner(inv, builder, cb)
 
@@ -148,15 +179,34 @@ func main() {
 		return
 	}
 
+	if *listTests {
+		var lists []simTestList
+		for _, sim := range simList {
+			suites, err := runner.ListTests(ctx, sim, env)
+			if err != nil {
+				fatal(err)
+			}
+			lists = append(lists, newSimTestList(sim, suites))
+		}
+		if err := printTestLists(os.Stdout, lists, *listFormat); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
 	// Run simulators.
 	var failCount int
 	for _, sim := range simList {
+		start := time.Now()
 		result, err := runner.Run(ctx, sim, env)
 		if err != nil {
 			fatal(err)
 		}
 		failCount += result.TestsFailed
-		log15.Info(fmt.Sprintf("simulation %s finished", sim), "suites", result.Suites, "tests", result.Tests, "failed", result.TestsFailed)
+		log15.Info(fmt.Sprintf("simulation %s finished", sim), "suites", result.Suites, "tests", result.Tests, "failed", result.TestsFailed, "skipped", result.TestsSkipped)
+		if notifier != nil {
+			sendNotifications(ctx, notifier, env.LogDir, sim, start, result)
+		}
 	}
 
 	switch failCount {
@@ -173,6 +223,103 @@ func fatal(args ...interface{}) {
 	os.Exit(1)
 }
 
+// startMetricsServer serves the metrics endpoint in the background.
+func startMetricsServer(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", libhive.MetricsHandler())
+	log15.Info("serving metrics", "url", fmt.Sprintf("http://%v/metrics", l.Addr()))
+	go http.Serve(l, mux)
+	return nil
+}
+
+// sendNotifications reports the result of a simulation run to the configured notifiers.
+func sendNotifications(ctx context.Context, n *notify.Dispatcher, logdir, sim string, start time.Time, result libhive.SimResult) {
+	report, err := notify.NewReport(logdir, sim, start, result)
+	if err != nil {
+		log15.Warn("can't read previous results for notification", "sim", sim, "err", err)
+	}
+	if err := n.Send(ctx, report); err != nil {
+		log15.Error("notification failed", "sim", sim, "err", err)
+	}
+}
+
+// simTestList is the output of --list-tests for a single simulator.
+type simTestList struct {
+	Simulator string        `json:"simulator"`
+	Suites    []listedSuite `json:"suites"`
+}
+
+type listedSuite struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Tests       []listedTest `json:"tests"`
+}
+
+type listedTest struct {
+	ID          libhive.TestID `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parent      libhive.TestID `json:"parent,omitempty"`
+}
+
+// newSimTestList converts the suites reported by a simulator in list mode.
+// Suites and tests are ordered by ID, i.e. in the order they were reported.
+func newSimTestList(sim string, suites map[libhive.TestSuiteID]*libhive.TestSuite) simTestList {
+	list := simTestList{Simulator: sim, Suites: []listedSuite{}}
+	suiteIDs := make([]libhive.TestSuiteID, 0, len(suites))
+	for id := range suites {
+		suiteIDs = append(suiteIDs, id)
+	}
+	sort.Slice(suiteIDs, func(i, j int) bool { return suiteIDs[i] < suiteIDs[j] })
+
+	for _, id := range suiteIDs {
+		suite := suites[id]
+		ls := listedSuite{Name: suite.Name, Description: suite.Description, Tests: []listedTest{}}
+		for testID, test := range suite.TestCases {
+			ls.Tests = append(ls.Tests, listedTest{
+				ID:          testID,
+				Name:        test.Name,
+				Description: test.Description,
+				Parent:      test.Parent,
+			})
+		}
+		sort.Slice(ls.Tests, func(i, j int) bool { return ls.Tests[i].ID < ls.Tests[j].ID })
+		list.Suites = append(list.Suites, ls)
+	}
+	return list
+}
+
+// printTestLists writes the output of --list-tests.
+func printTestLists(w io.Writer, lists []simTestList, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(lists)
+	}
+
+	for _, list := range lists {
+		var count int
+		fmt.Fprintf(w, "%s\n", list.Simulator)
+		for _, suite := range list.Suites {
+			fmt.Fprintf(w, "  %s\n", suite.Name)
+			depth := make(map[libhive.TestID]int, len(suite.Tests))
+			for _, test := range suite.Tests {
+				if test.Parent != 0 {
+					depth[test.ID] = depth[test.Parent] + 1
+				}
+				fmt.Fprintf(w, "    %s%s\n", strings.Repeat("  ", depth[test.ID]), test.Name)
+			}
+			count += len(suite.Tests)
+		}
+		fmt.Fprintf(w, "%d suites, %d tests\n", len(list.Suites), count)
+	}
+	return nil
+}
+
 func parseClientsFile(inv *libhive.Inventory, file string) ([]libhive.ClientDesignator, error) {
 	f, err := os.Open(file)
 	if err != nil {
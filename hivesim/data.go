@@ -1,5 +1,7 @@
 package hivesim
 
+import "github.com/ethereum/hive/internal/simapi"
+
 // SuiteID identifies a test suite context.
 type SuiteID uint32
 
@@ -9,6 +11,7 @@ type TestID uint32
 // TestResult describes the outcome of a test.
 type TestResult struct {
 	Pass    bool   `json:"pass"`
+	Skipped bool   `json:"skipped,omitempty"`
 	Details string `json:"details"`
 }
 
@@ -19,9 +22,41 @@ type ExecInfo struct {
 	ExitCode int    `json:"exitCode"`
 }
 
+// Well-known client port names.
+const (
+	PortRPC       = simapi.PortRPC       // JSON-RPC over HTTP
+	PortWS        = simapi.PortWS        // JSON-RPC over WebSocket
+	PortEngine    = simapi.PortEngine    // engine API
+	PortBeaconAPI = simapi.PortBeaconAPI // beacon node HTTP API
+	PortP2P       = simapi.PortP2P       // peer-to-peer networking
+	PortMetrics   = simapi.PortMetrics   // metrics endpoint
+)
+
+// defaultPorts are used when the client metadata does not declare a port.
+var defaultPorts = map[string]uint16{
+	PortRPC:       8545,
+	PortWS:        8546,
+	PortEngine:    8551,
+	PortBeaconAPI: 4000,
+	PortP2P:       30303,
+}
+
 // ClientMetadata is part of the ClientDefinition and lists metadata
 type ClientMetadata struct {
-	Roles []string `yaml:"roles" json:"roles"`
+	Roles    []string          `yaml:"roles" json:"roles"`
+	Ports    map[string]uint16 `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Forks    []string          `yaml:"forks,omitempty" json:"forks,omitempty"`
+	Features []string          `yaml:"features,omitempty" json:"features,omitempty"`
+}
+
+// Port returns the TCP port with the given name. For well-known port names,
+// the default port is returned if the client does not declare it.
+func (m *ClientMetadata) Port(name string) (uint16, bool) {
+	if port, ok := m.Ports[name]; ok {
+		return port, true
+	}
+	port, ok := defaultPorts[name]
+	return port, ok
 }
 
 // ClientDefinition is served by the /clients API endpoint to list the available clients
@@ -40,3 +75,23 @@ func (m *ClientDefinition) HasRole(role string) bool {
 	}
 	return false
 }
+
+// HasFeature reports whether the client implements the given feature.
+func (m *ClientDefinition) HasFeature(feature string) bool {
+	return contains(m.Meta.Features, feature)
+}
+
+// SupportsFork reports whether the client supports the given fork.
+// Clients which do not declare their supported forks are assumed to support all forks.
+func (m *ClientDefinition) SupportsFork(fork string) bool {
+	return len(m.Meta.Forks) == 0 || contains(m.Meta.Forks, fork)
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
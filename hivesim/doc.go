@@ -62,7 +62,7 @@ It is also possible to add a test case to the test suite without using the two a
 implements the following interface:
 
 	type AnyTest interface {
-		runTest(*Simulation, SuiteID) error
+		runTest(*Simulation, testContext) error
 	}
 
 # Creating a Test Run
@@ -85,6 +85,11 @@ of the client, and `RPC()`, which returns an RPC client connected to the client'
 `T` can also run a test against a client using any of the `Run__()` methods. It can also pipe logs and test
 failures through to the simulation log file, among other methods.
 
+Teardown of resources created by a test, such as mock servers or proxies, can be registered using
+`t.Cleanup()`. Cleanup functions run in reverse order of registration after the test function and its
+subtests have finished, even if the test panics or calls `t.FailNow()`. They run before hive stops the
+clients of the test.
+
 The `Sim` field (which is a pointer to an instance of `Simulation`) in the `T` object is especially useful as it
 provides several methods for communicating with the hive simulation API, such as:
 
@@ -94,6 +99,69 @@ provides several methods for communicating with the hive simulation API, such as
   - connecting / disconnecting containers to/from a network
   - getting the IP address of a container on a specific network
 
+# Parallel Tests
+
+Tests can opt into running in parallel with other tests by calling `t.Parallel()`. The call
+returns control to the caller of `t.Run` (or `RunSuite`), and the test continues once a
+slot is available. The number of parallel tests running at the same time is limited by the
+`HIVE_PARALLELISM` environment variable, which is set by hive. A parent test, or the suite,
+does not end until all of its parallel subtests have ended.
+
+	func myTest(t *hivesim.T) {
+		for _, tc := range testcases {
+			tc := tc
+			t.Run(hivesim.TestSpec{
+				Name: tc.name,
+				Run: func(t *hivesim.T) {
+					t.Parallel()
+					tc.run(t)
+				},
+			})
+		}
+	}
+
+# Waiting for Conditions
+
+Tests often need to wait for a client to reach some state. `t.Eventually` polls a
+condition function until it returns nil, failing the test when the context is done first.
+The error returned by the condition should describe the current state. On failure, all
+attempts are logged in the test details, with repeated outcomes merged:
+
+	t.Eventually(ctx, time.Second, func() error {
+		if n := getBalance(); n < want {
+			return fmt.Errorf("balance is %d, want %d", n, want)
+		}
+		return nil
+	})
+
+The helpers `t.WaitForBlock`, `t.WaitForPeers` and `t.WaitForSyncComplete` implement
+common conditions using the client's RPC interface.
+
+# Fixture Tests
+
+Simulators which run tests from files can use `FixtureSpec`. The runner walks a directory,
+loads every matching file using the given `Load` function, and runs each fixture as a
+test. Fixture tests are filtered by the test pattern, can run in parallel, and are
+included in generated documentation.
+
+	suite.Add(hivesim.FixtureSpec{
+		Root:     "./tests",
+		Pattern:  "*.json",
+		Parallel: true,
+		Load: func(path string) ([]hivesim.Fixture, error) {
+			tc, err := loadTestCase(path)
+			if err != nil {
+				return nil, err
+			}
+			return []hivesim.Fixture{{Name: tc.name, Data: tc}}, nil
+		},
+		Run: func(t *hivesim.T, f *hivesim.Fixture) {
+			f.Data.(*testCase).run(t)
+		},
+	})
+
+To run fixtures against a client launched by a test, use `t.RunFixtures`.
+
 # Running a Test Suite
 
 It is possible to call either `RunSuite()` or `MustRunSuite()` on the `Suite`, the only difference being the
@@ -0,0 +1,136 @@
+package hivesim
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// Fixture is a test case loaded from a fixture file.
+type Fixture struct {
+	Name        string // Name of the test. If empty, the file path is used.
+	Description string // Description of the test [Optional]
+
+	// File is the path of the fixture file, relative to the fixture root.
+	// This is set by the runner.
+	File string
+
+	// Data is the content of the fixture, as returned by the loader.
+	Data any
+}
+
+// FixtureSpec is a set of tests loaded from fixture files. It can be added to a suite
+// directly, or launched using t.RunFixtures from another test.
+//
+// The runner walks the Root directory and calls Load for every file matching Pattern.
+// Every fixture returned by Load becomes a test, which executes Run. Fixture tests are
+// selected by the test pattern like any other test. If a file cannot be loaded, a failing
+// test named after the file is reported.
+//
+// In docs mode, the fixtures are loaded, but Run is not executed.
+type FixtureSpec struct {
+	// Root is the directory containing the fixture files. It is walked recursively.
+	Root string
+
+	// Pattern selects fixture files by name, using the syntax of filepath.Match,
+	// e.g. "*.json". If empty, all files are loaded.
+	Pattern string
+
+	// Category is the category of all fixture tests [Optional]
+	Category string
+
+	// If Parallel is true, fixture tests run in parallel, as if they called t.Parallel.
+	Parallel bool
+
+	// Load reads the fixtures contained in a file.
+	Load func(path string) ([]Fixture, error)
+
+	// The Run function is invoked for each fixture.
+	Run func(*T, *Fixture)
+}
+
+// RunFixtures runs the given fixture tests as subtests of t. It waits for all
+// subtests to complete, unless they are parallel.
+func (t *T) RunFixtures(spec FixtureSpec) {
+	if err := spec.runTest(t.Sim, t.subtestContext()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (spec FixtureSpec) runTest(host *Simulation, ctx testContext) error {
+	files, err := spec.files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		rel, err := filepath.Rel(spec.Root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		fixtures, loadErr := spec.Load(file)
+		if loadErr != nil {
+			test := testSpec{ctx: ctx, name: rel, category: spec.Category}
+			err := runTest(host, test, func(t *T) {
+				t.Fatalf("can't load %s: %v", rel, loadErr)
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for i := range fixtures {
+			fixture := &fixtures[i]
+			fixture.File = rel
+			if fixture.Name == "" {
+				fixture.Name = rel
+			}
+			test := testSpec{
+				ctx:      ctx,
+				name:     fixture.Name,
+				category: spec.Category,
+				desc:     fixture.Description,
+			}
+			err := runTest(host, test, func(t *T) {
+				if spec.Parallel {
+					t.Parallel()
+				}
+				spec.Run(t, fixture)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// files returns the fixture files in lexical order.
+func (spec FixtureSpec) files() ([]string, error) {
+	if spec.Pattern != "" {
+		if _, err := filepath.Match(spec.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid fixture pattern %q: %v", spec.Pattern, err)
+		}
+	}
+	var files []string
+	err := filepath.WalkDir(spec.Root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if spec.Pattern != "" {
+			if ok, _ := filepath.Match(spec.Pattern, entry.Name()); !ok {
+				return nil
+			}
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't load fixtures: %v", err)
+	}
+	return files, nil
+}
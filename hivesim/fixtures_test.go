@@ -0,0 +1,104 @@
+package hivesim
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "a.json"), []byte(`["a1", "a2", "skipped"]`), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b.json"), []byte(`[""]`), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "bad.json"), []byte(`{`), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`not a fixture`), 0644)
+
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	spec := FixtureSpec{
+		Root:     dir,
+		Pattern:  "*.json",
+		Parallel: true,
+		Load: func(path string) ([]Fixture, error) {
+			var names []string
+			if err := json.Unmarshal(mustRead(t, path), &names); err != nil {
+				return nil, err
+			}
+			fixtures := make([]Fixture, len(names))
+			for i, name := range names {
+				fixtures[i] = Fixture{Name: name, Data: i}
+			}
+			return fixtures, nil
+		},
+		Run: func(t *T, f *Fixture) {
+			mu.Lock()
+			ran = append(ran, f.File+":"+f.Name)
+			mu.Unlock()
+		},
+	}
+	suite := Suite{Name: "fixtures"}
+	suite.Add(spec)
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	sim := NewAt(srv.URL)
+	sim.SetTestPattern("fixtures/a1|a2|sub/")
+	if err := RunSuite(sim, suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	sort.Strings(ran)
+	wantRan := []string{"a.json:a1", "a.json:a2", "sub/b.json:sub/b.json"}
+	if !reflect.DeepEqual(ran, wantRan) {
+		t.Errorf("wrong fixtures run: %q, want %q", ran, wantRan)
+	}
+	results := make(map[string]bool)
+	for _, tc := range tm.Results()[0].TestCases {
+		results[tc.Name] = tc.SummaryResult.Pass
+	}
+	wantResults := map[string]bool{"a1": true, "a2": true, "sub/b.json": true, "sub/bad.json": false}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("wrong test results: %v, want %v", results, wantResults)
+	}
+}
+
+func TestFixturesMissingRoot(t *testing.T) {
+	suite := Suite{Name: "fixtures"}
+	suite.Add(TestSpec{
+		Name: "parent",
+		Run: func(t *T) {
+			t.RunFixtures(FixtureSpec{
+				Root: filepath.Join(os.TempDir(), "hivesim-does-not-exist"),
+				Load: func(string) ([]Fixture, error) { return nil, nil },
+				Run:  func(*T, *Fixture) {},
+			})
+		},
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if tm.Results()[0].TestCases[1].SummaryResult.Pass {
+		t.Fatal("test with missing fixture root passed")
+	}
+}
+
+func mustRead(t *testing.T, file string) []byte {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Error(err)
+	}
+	return data
+}
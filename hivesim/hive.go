@@ -10,10 +10,12 @@ import (
 	"mime/multipart"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/hive/internal/simapi"
@@ -21,10 +23,17 @@ import (
 
 // Simulation wraps the simulation HTTP API provided by hive.
 type Simulation struct {
-	url  string
-	m    testMatcher
-	docs *docsCollector
-	ll   int
+	url   string
+	m     testMatcher
+	docs  *docsCollector
+	ll    int
+	sched *scheduler
+
+	// In list mode, tests are reported to hive, but not run.
+	listTests bool
+
+	clientsMu sync.Mutex
+	clients   map[string]*ClientDefinition // cached result of ClientTypes
 }
 
 // New looks up the hive host URI using the HIVE_SIMULATOR environment variable
@@ -46,7 +55,7 @@ func New() *Simulation {
 			panic("HIVE_SIMULATOR environment variable is empty")
 		}
 	}
-	sim := &Simulation{url: url, docs: docs}
+	sim := &Simulation{url: url, docs: docs, sched: newScheduler(parallelismFromEnv())}
 	if p := os.Getenv("HIVE_TEST_PATTERN"); p != "" {
 		m, err := parseTestPattern(p)
 		if err != nil {
@@ -57,13 +66,14 @@ func New() *Simulation {
 	if ll := os.Getenv("HIVE_LOGLEVEL"); ll != "" {
 		sim.ll, _ = strconv.Atoi(ll)
 	}
+	sim.listTests = os.Getenv("HIVE_LIST_TESTS") == "true"
 	return sim
 }
 
 // NewAt creates a simulation connected to the given API endpoint. You'll will rarely need
 // to use this. In simulations launched by hive, use New() instead.
 func NewAt(url string) *Simulation {
-	return &Simulation{url: url}
+	return &Simulation{url: url, sched: newScheduler(defaultParallelism)}
 }
 
 // SetTestPattern sets the regular expression that enables/skips suites and test cases.
@@ -77,6 +87,18 @@ func (sim *Simulation) SetTestPattern(p string) {
 	sim.m = m
 }
 
+// SetParallelism sets the maximum number of parallel tests which run at the same time.
+// This method is provided for use in unit tests. For simulator runs launched by hive, the
+// limit is set from HIVE_PARALLELISM in New(). It must not be called while tests are running.
+func (sim *Simulation) SetParallelism(n int) {
+	sim.sched = newScheduler(n)
+}
+
+// Parallelism returns the maximum number of parallel tests.
+func (sim *Simulation) Parallelism() int {
+	return sim.sched.limit()
+}
+
 // TestPattern returns the regular expressions used to enable/skip suite and test names.
 func (sim *Simulation) TestPattern() (suiteExpr string, testNameExpr string) {
 	se := ""
@@ -91,8 +113,10 @@ func (sim *Simulation) TestPattern() (suiteExpr string, testNameExpr string) {
 }
 
 // CollectTestsOnly returns true if the simulation is running in collect-tests-only mode.
+// This is the case when generating documentation, and when hive lists tests. AlwaysRun
+// tests still run in this mode, and should check CollectTestsOnly before launching clients.
 func (sim *Simulation) CollectTestsOnly() bool {
-	return sim.docs != nil
+	return sim.docs != nil || sim.listTests
 }
 
 // EndTest finishes the test case, cleaning up everything, logging results, and returning
@@ -156,6 +180,28 @@ func (sim *Simulation) ClientTypes() ([]*ClientDefinition, error) {
 	return resp, err
 }
 
+// clientDefinition returns the definition of the given client type.
+func (sim *Simulation) clientDefinition(clientType string) (*ClientDefinition, error) {
+	sim.clientsMu.Lock()
+	defer sim.clientsMu.Unlock()
+
+	if sim.clients == nil {
+		defs, err := sim.ClientTypes()
+		if err != nil {
+			return nil, err
+		}
+		sim.clients = make(map[string]*ClientDefinition, len(defs))
+		for _, def := range defs {
+			sim.clients[def.Name] = def
+		}
+	}
+	def, ok := sim.clients[clientType]
+	if !ok {
+		return nil, fmt.Errorf("unknown client type %q", clientType)
+	}
+	return def, nil
+}
+
 // StartClient starts a new node (or other container) with the specified parameters. One
 // parameter must be named CLIENT and should contain one of the client types from
 // GetClientTypes. The input is used as environment variables in the new container.
@@ -306,6 +352,21 @@ func (sim *Simulation) ClientExec(testSuite SuiteID, test TestID, nodeid string,
 	return resp, err
 }
 
+// AttachArtifact stores the content of data as a file attached to the test result.
+// The name must be a file name without directory components.
+func (sim *Simulation) AttachArtifact(testSuite SuiteID, test TestID, name string, data io.Reader) error {
+	if sim.docs != nil {
+		return nil
+	}
+	url := fmt.Sprintf("%s/testsuite/%d/test/%d/artifact/%s", sim.url, testSuite, test, neturl.PathEscape(name))
+	httpReq, err := http.NewRequest("POST", url, data)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("content-type", "application/octet-stream")
+	return request(httpReq, nil)
+}
+
 // CreateNetwork sends a request to the hive server to create a docker network by
 // the given name.
 func (sim *Simulation) CreateNetwork(testSuite SuiteID, networkName string) error {
@@ -36,7 +36,11 @@ func TestClientTypes(t *testing.T) {
 		{
 			Name:    "client-2",
 			Version: "client-2-version",
-			Meta:    ClientMetadata{Roles: []string{"beacon"}},
+			Meta: ClientMetadata{
+				Roles:    []string{"beacon"},
+				Ports:    map[string]uint16{"beacon-api": 5052},
+				Features: []string{"light-client"},
+			},
 		},
 	}
 	if !reflect.DeepEqual(ctypes, wantClients) {
@@ -400,10 +404,49 @@ func TestStartClientInitialNetworks(t *testing.T) {
 	}
 }
 
+// This test checks that the parent test is recorded by StartTest.
+func TestStartTestParent(t *testing.T) {
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	parentID, err := sim.StartTest(suiteID, &simapi.TestRequest{Name: "parent"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	childID, err := sim.StartTest(suiteID, &simapi.TestRequest{Name: "child", Parent: uint32(parentID)})
+	if err != nil {
+		t.Fatal("can't start subtest:", err)
+	}
+	if _, err := sim.StartTest(suiteID, &simapi.TestRequest{Name: "orphan", Parent: 1000}); err == nil {
+		t.Fatal("no error for unknown parent test")
+	}
+	sim.EndTest(suiteID, childID, TestResult{Pass: true})
+	sim.EndTest(suiteID, parentID, TestResult{Pass: true})
+	sim.EndSuite(suiteID)
+
+	cases := tm.Results()[0].TestCases
+	if p := cases[libhive.TestID(childID)].Parent; p != libhive.TestID(parentID) {
+		t.Fatalf("wrong parent %d for subtest, want %d", p, parentID)
+	}
+	if p := cases[libhive.TestID(parentID)].Parent; p != 0 {
+		t.Fatalf("top-level test has parent %d", p)
+	}
+}
+
 func newFakeAPI(hooks *fakes.BackendHooks) (*libhive.TestManager, *httptest.Server) {
 	defs := []*libhive.ClientDefinition{
 		{Name: "client-1", Image: "/ignored/in/api", Version: "client-1-version", Meta: libhive.ClientMetadata{Roles: []string{"eth1"}}},
-		{Name: "client-2", Image: "/not/exposed/", Version: "client-2-version", Meta: libhive.ClientMetadata{Roles: []string{"beacon"}}},
+		{Name: "client-2", Image: "/not/exposed/", Version: "client-2-version", Meta: libhive.ClientMetadata{
+			Roles:    []string{"beacon"},
+			Ports:    map[string]uint16{"beacon-api": 5052},
+			Features: []string{"light-client"},
+		}},
 	}
 	env := libhive.SimEnv{}
 	backend := fakes.NewContainerBackend(hooks)
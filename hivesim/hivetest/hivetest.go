@@ -0,0 +1,146 @@
+// Package hivetest runs hivesim simulations in-process, without hive and docker.
+//
+// This package is meant for use in Go tests of simulators. It starts a simulation API
+// server backed by a stand-in for docker, and provides access to the test results
+// reported by the simulator:
+//
+//	func TestMySuite(t *testing.T) {
+//		env := hivetest.New(t, hivetest.Config{
+//			Clients: []hivetest.Client{{Name: "go-ethereum"}},
+//		})
+//		hivesim.MustRunSuite(env.Sim, mySuite)
+//
+//		results := env.Results()
+//		if tc := hivetest.FindTest(results[0], "my test"); !tc.SummaryResult.Pass {
+//			t.Fatal("my test failed:", tc.SummaryResult.Details)
+//		}
+//	}
+//
+// By default, clients are simulated by a fake backend which doesn't launch anything.
+// Use NewLocalBackend to run client binaries as local processes.
+package hivetest
+
+import (
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/internal/fakes"
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+// These are the result types reported by the simulation API.
+type (
+	TestSuite      = libhive.TestSuite
+	TestCase       = libhive.TestCase
+	TestResult     = libhive.TestResult
+	ClientMetadata = libhive.ClientMetadata
+)
+
+// These types are used for implementing backends.
+type (
+	// Backend launches clients. It is implemented by the fake backend and
+	// the local process backend.
+	Backend          = libhive.ContainerBackend
+	ContainerOptions = libhive.ContainerOptions
+	ContainerInfo    = libhive.ContainerInfo
+	ExecInfo         = libhive.ExecInfo
+
+	// BackendHooks can be used to override the behavior of the fake backend.
+	BackendHooks = fakes.BackendHooks
+)
+
+// NewFakeBackend creates a backend which doesn't launch any clients. Client
+// operations succeed unless they are overridden by hooks.
+func NewFakeBackend(hooks *BackendHooks) Backend {
+	return fakes.NewContainerBackend(hooks)
+}
+
+// Client is a client type available to the simulation.
+type Client struct {
+	Name    string
+	Version string
+	Meta    ClientMetadata
+}
+
+// Config configures the simulation environment.
+type Config struct {
+	// Clients lists the available client types.
+	Clients []Client
+
+	// Backend launches clients. If nil, a fake backend is used.
+	Backend Backend
+
+	// TestPattern selects suites and tests, like the --sim.limit flag of hive.
+	TestPattern string
+
+	// LogDir is the directory where test results and client logs are written.
+	// If empty, no files are written.
+	LogDir string
+}
+
+// Env is an in-process simulation environment.
+type Env struct {
+	// Sim is connected to the simulation API of the environment.
+	Sim *hivesim.Simulation
+
+	tm  *libhive.TestManager
+	srv *httptest.Server
+}
+
+// New starts a simulation environment. It is shut down when the test ends.
+func New(t testing.TB, cfg Config) *Env {
+	backend := cfg.Backend
+	if backend == nil {
+		backend = NewFakeBackend(nil)
+	}
+	defs := make([]*libhive.ClientDefinition, len(cfg.Clients))
+	for i, c := range cfg.Clients {
+		defs[i] = &libhive.ClientDefinition{Name: c.Name, Version: c.Version, Image: c.Name, Meta: c.Meta}
+	}
+	simEnv := libhive.SimEnv{LogDir: cfg.LogDir, SimTestPattern: cfg.TestPattern}
+
+	env := &Env{tm: libhive.NewTestManager(simEnv, backend, defs)}
+	env.srv = httptest.NewServer(env.tm.API())
+	env.Sim = hivesim.NewAt(env.srv.URL)
+	if cfg.TestPattern != "" {
+		env.Sim.SetTestPattern(cfg.TestPattern)
+	}
+	t.Cleanup(env.Close)
+	return env
+}
+
+// URL returns the URL of the simulation API. To run the main function of a
+// simulator against the environment, set HIVE_SIMULATOR to this URL.
+func (env *Env) URL() string {
+	return env.srv.URL
+}
+
+// Results returns the results of all suites which have ended, ordered by suite ID.
+func (env *Env) Results() []*TestSuite {
+	results := env.tm.Results()
+	suites := make([]*TestSuite, 0, len(results))
+	for _, s := range results {
+		suites = append(suites, s)
+	}
+	sort.Slice(suites, func(i, j int) bool { return suites[i].ID < suites[j].ID })
+	return suites
+}
+
+// Close terminates all running clients and suites, and stops the API server.
+func (env *Env) Close() {
+	env.tm.Terminate()
+	env.srv.Close()
+}
+
+// FindTest returns the test case with the given name, or nil if the suite
+// doesn't contain such a test.
+func FindTest(suite *TestSuite, name string) *TestCase {
+	for _, tc := range suite.TestCases {
+		if tc.Name == name {
+			return tc
+		}
+	}
+	return nil
+}
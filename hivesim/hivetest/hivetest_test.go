@@ -0,0 +1,163 @@
+package hivetest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/hive/hivesim"
+)
+
+func TestFakeBackend(t *testing.T) {
+	env := New(t, Config{
+		Clients:     []Client{{Name: "client-1"}, {Name: "client-2"}},
+		TestPattern: "suite/client-1",
+	})
+	suite := hivesim.Suite{Name: "suite"}
+	suite.Add(hivesim.ClientTestSpec{
+		Name: "CLIENT",
+		Run: func(t *hivesim.T, c *hivesim.Client) {
+			t.Log("client IP:", c.IP)
+		},
+	})
+	if err := hivesim.RunSuite(env.Sim, suite); err != nil {
+		t.Fatal(err)
+	}
+
+	results := env.Results()
+	if len(results) != 1 || len(results[0].TestCases) != 1 {
+		t.Fatalf("wrong results: %+v", results)
+	}
+	tc := FindTest(results[0], "client-1")
+	if tc == nil {
+		t.Fatal("test not found")
+	}
+	if !tc.SummaryResult.Pass || !strings.Contains(tc.SummaryResult.Details, "client IP: 192.0.2.") {
+		t.Fatalf("wrong result: %+v", tc.SummaryResult)
+	}
+}
+
+// This test runs the test binary as a client process. The client serves
+// HTTP on its RPC port, responding with the content of an uploaded file.
+func TestLocalBackend(t *testing.T) {
+	backend := NewLocalBackend(map[string]LocalClient{
+		"client": func(inst *LocalInstance) (*exec.Cmd, error) {
+			cmd := exec.Command(os.Args[0], "-test.run=TestHelperClient")
+			cmd.Env = []string{
+				"HIVETEST_HELPER_CLIENT=1",
+				"HIVETEST_ADDR=" + net.JoinHostPort(inst.IP.String(), "8545"),
+				"HIVETEST_DIR=" + inst.Dir,
+			}
+			return cmd, nil
+		},
+	})
+	env := New(t, Config{Clients: []Client{{Name: "client"}}, Backend: backend})
+
+	suite := hivesim.Suite{Name: "local"}
+	suite.Add(hivesim.TestSpec{
+		Name: "test",
+		Run: func(t *hivesim.T) {
+			c := t.StartClient("client", hivesim.Params{"HIVE_VALUE": "x"}, hivesim.WithDynamicFile("/data.txt", func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("file content")), nil
+			}))
+			resp, err := http.Get(fmt.Sprintf("http://%v:8545", c.IP))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "x file content" {
+				t.Fatalf("wrong response %q", body)
+			}
+		},
+	})
+	if err := hivesim.RunSuite(env.Sim, suite); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := FindTest(env.Results()[0], "test")
+	if !tc.SummaryResult.Pass {
+		t.Fatal("test failed:", tc.SummaryResult.Details)
+	}
+}
+
+// This test checks that no client logs or artifacts are written when Config.LogDir
+// is empty.
+func TestNoLogDir(t *testing.T) {
+	backend := NewLocalBackend(map[string]LocalClient{
+		"client": func(inst *LocalInstance) (*exec.Cmd, error) {
+			cmd := exec.Command(os.Args[0], "-test.run=TestHelperClient")
+			cmd.Env = []string{
+				"HIVETEST_HELPER_CLIENT=1",
+				"HIVETEST_ADDR=" + net.JoinHostPort(inst.IP.String(), "8545"),
+				"HIVETEST_DIR=" + inst.Dir,
+			}
+			return cmd, nil
+		},
+	})
+	chdir(t, t.TempDir())
+	env := New(t, Config{Clients: []Client{{Name: "client"}}, Backend: backend})
+
+	suite := hivesim.Suite{Name: "nologdir"}
+	suite.Add(hivesim.TestSpec{
+		Name: "test",
+		Run: func(t *hivesim.T) {
+			t.StartClient("client", hivesim.Params{"HIVE_VALUE": "x"}, hivesim.WithDynamicFile("/data.txt", func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("file content")), nil
+			}))
+			t.Attach("artifact.txt", strings.NewReader("artifact content"))
+		},
+	})
+	if err := hivesim.RunSuite(env.Sim, suite); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := FindTest(env.Results()[0], "test")
+	if !tc.SummaryResult.Pass {
+		t.Fatal("test failed:", tc.SummaryResult.Details)
+	}
+	for _, c := range tc.ClientInfo {
+		if c.LogFile != "" {
+			t.Errorf("client has log file %q", c.LogFile)
+		}
+	}
+	if len(tc.Artifacts) != 0 {
+		t.Errorf("test has artifacts: %+v", tc.Artifacts)
+	}
+	if files, _ := os.ReadDir("."); len(files) != 0 {
+		t.Fatalf("files written to the working directory: %v", files)
+	}
+}
+
+// chdir changes the working directory for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestHelperClient(t *testing.T) {
+	if os.Getenv("HIVETEST_HELPER_CLIENT") != "1" {
+		return
+	}
+	data, err := os.ReadFile(os.Getenv("HIVETEST_DIR") + "/data.txt")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	response := os.Getenv("HIVE_VALUE") + " " + string(data)
+	http.ListenAndServe(os.Getenv("HIVETEST_ADDR"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, response)
+	}))
+	os.Exit(1)
+}
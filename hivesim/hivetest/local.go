@@ -0,0 +1,279 @@
+package hivetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+var errNotSupported = errors.New("operation not supported by local backend")
+
+// LocalClient creates the command which runs a client instance.
+type LocalClient func(inst *LocalInstance) (*exec.Cmd, error)
+
+// LocalInstance describes a client instance launched by the local backend.
+type LocalInstance struct {
+	Client string            // client type
+	ID     string            // instance ID
+	IP     net.IP            // loopback address assigned to the instance
+	Dir    string            // directory containing the files uploaded by the simulator
+	Env    map[string]string // HIVE_* environment variables set by the simulator
+}
+
+// localBackend runs clients as processes on the local machine.
+type localBackend struct {
+	clients map[string]LocalClient
+
+	mu        sync.Mutex
+	counter   uint64
+	instances map[string]*localProcess
+}
+
+type localProcess struct {
+	inst *LocalInstance
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// NewLocalBackend creates a backend which launches clients as local processes. The
+// clients map contains the launcher of each client type.
+//
+// Every instance is assigned its own address in 127.0.0.0/8, and the launcher should
+// make the client listen on this address. Files uploaded by the simulator are written
+// to the instance directory, with the same relative path as in a client container.
+// Note that loopback addresses other than 127.0.0.1 are only usable on Linux.
+//
+// Client scripts are run as local commands in the instance directory. Networks and
+// pausing clients are not supported.
+func NewLocalBackend(clients map[string]LocalClient) Backend {
+	return &localBackend{clients: clients, instances: make(map[string]*localProcess)}
+}
+
+func (b *localBackend) Build(context.Context, libhive.Builder) error {
+	return nil
+}
+
+func (b *localBackend) ServeAPI(ctx context.Context, h http.Handler) (libhive.APIServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: h}
+	go srv.Serve(l)
+	return localAPIServer{srv, l.Addr()}, nil
+}
+
+type localAPIServer struct {
+	s    *http.Server
+	addr net.Addr
+}
+
+func (s localAPIServer) Close() error   { return s.s.Close() }
+func (s localAPIServer) Addr() net.Addr { return s.addr }
+
+func (b *localBackend) CreateContainer(ctx context.Context, image string, opt libhive.ContainerOptions) (string, error) {
+	launch, ok := b.clients[image]
+	if !ok {
+		return "", fmt.Errorf("no local launcher for client %q", image)
+	}
+
+	b.mu.Lock()
+	b.counter++
+	n := b.counter
+	b.mu.Unlock()
+
+	dir, err := os.MkdirTemp("", "hivetest-"+image+"-")
+	if err != nil {
+		return "", err
+	}
+	inst := &LocalInstance{
+		Client: image,
+		ID:     fmt.Sprintf("%0.8x", n),
+		IP:     net.IP{127, 0, byte(n / 250), byte(n%250) + 2},
+		Dir:    dir,
+		Env:    opt.Env,
+	}
+	if err := writeFiles(dir, opt); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	cmd, err := launch(inst)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	cmd.Env = append(os.Environ(), cmd.Env...)
+	for k, v := range opt.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	b.mu.Lock()
+	b.instances[inst.ID] = &localProcess{inst: inst, cmd: cmd, done: make(chan struct{})}
+	b.mu.Unlock()
+	return inst.ID, nil
+}
+
+// writeFiles writes the files uploaded by the simulator to the instance directory.
+func writeFiles(dir string, opt libhive.ContainerOptions) error {
+	for path, fh := range opt.Files {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		src, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, content, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) StartContainer(ctx context.Context, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+	p, err := b.instance(containerID)
+	if err != nil {
+		return nil, err
+	}
+	var logfile *os.File
+	if opt.LogFile != "" {
+		if err := os.MkdirAll(filepath.Dir(opt.LogFile), 0755); err != nil {
+			return nil, err
+		}
+		if logfile, err = os.Create(opt.LogFile); err != nil {
+			return nil, err
+		}
+		p.cmd.Stdout, p.cmd.Stderr = logfile, logfile
+	}
+	if err := p.cmd.Start(); err != nil {
+		if logfile != nil {
+			logfile.Close()
+		}
+		return nil, err
+	}
+	go func() {
+		p.cmd.Wait()
+		if logfile != nil {
+			logfile.Close()
+		}
+		close(p.done)
+	}()
+
+	info := &libhive.ContainerInfo{
+		ID:      containerID,
+		IP:      p.inst.IP.String(),
+		LogFile: opt.LogFile,
+		Wait:    func() { <-p.done },
+	}
+	if opt.CheckLive != 0 {
+		addr := net.JoinHostPort(info.IP, strconv.Itoa(int(opt.CheckLive)))
+		if err := waitForPort(ctx, addr, p.done); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// waitForPort waits until addr accepts TCP connections.
+func waitForPort(ctx context.Context, addr string, exited <-chan struct{}) error {
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for %s", addr)
+		case <-exited:
+			return errors.New("client process exited")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (b *localBackend) DeleteContainer(containerID string) error {
+	p, err := b.instance(containerID)
+	if err != nil {
+		return err
+	}
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+		<-p.done
+	}
+	b.mu.Lock()
+	delete(b.instances, containerID)
+	b.mu.Unlock()
+	return os.RemoveAll(p.inst.Dir)
+}
+
+func (b *localBackend) instance(id string) (*localProcess, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.instances[id]
+	if !ok {
+		return nil, fmt.Errorf("client instance %s does not exist", id)
+	}
+	return p, nil
+}
+
+// RunProgram runs the command on the local machine. The working directory is
+// the instance directory.
+func (b *localBackend) RunProgram(ctx context.Context, containerID string, cmdline []string) (*libhive.ExecInfo, error) {
+	p, err := b.instance(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cmdline) == 0 {
+		return nil, errors.New("empty command")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	cmd.Dir = p.inst.Dir
+	cmd.Env = p.cmd.Env
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	info := &libhive.ExecInfo{Stdout: stdout.String(), Stderr: stderr.String()}
+	if exitErr := new(exec.ExitError); errors.As(err, &exitErr) {
+		info.ExitCode = exitErr.ExitCode()
+		err = nil
+	}
+	return info, err
+}
+
+func (b *localBackend) PauseContainer(containerID string) error   { return errNotSupported }
+func (b *localBackend) UnpauseContainer(containerID string) error { return errNotSupported }
+
+func (b *localBackend) NetworkNameToID(name string) (string, error) {
+	return "", libhive.ErrNetworkNotFound
+}
+func (b *localBackend) CreateNetwork(name string) (string, error) { return "", errNotSupported }
+func (b *localBackend) RemoveNetwork(id string) error             { return errNotSupported }
+func (b *localBackend) ContainerIP(containerID, networkID string) (net.IP, error) {
+	return nil, errNotSupported
+}
+func (b *localBackend) ConnectContainer(containerID, networkID string) error {
+	return errNotSupported
+}
+func (b *localBackend) DisconnectContainer(containerID, networkID string) error {
+	return errNotSupported
+}
@@ -0,0 +1,44 @@
+package hivesim
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// defaultParallelism is used when HIVE_PARALLELISM is not set.
+const defaultParallelism = 1
+
+// scheduler limits the number of parallel tests which are running at the same time.
+type scheduler struct {
+	slots chan struct{}
+}
+
+func newScheduler(n int) *scheduler {
+	if n < 1 {
+		n = 1
+	}
+	return &scheduler{slots: make(chan struct{}, n)}
+}
+
+// acquire blocks until a test slot is available.
+func (s *scheduler) acquire() { s.slots <- struct{}{} }
+
+// release returns a slot taken by acquire.
+func (s *scheduler) release() { <-s.slots }
+
+func (s *scheduler) limit() int { return cap(s.slots) }
+
+// parallelismFromEnv reads HIVE_PARALLELISM.
+func parallelismFromEnv() int {
+	val, ok := os.LookupEnv("HIVE_PARALLELISM")
+	if !ok {
+		return defaultParallelism
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid HIVE_PARALLELISM value %q\n", val)
+		return defaultParallelism
+	}
+	return n
+}
@@ -3,6 +3,7 @@ package hivesim
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"runtime"
@@ -41,7 +42,15 @@ func (s *Suite) Add(test AnyTest) *Suite {
 
 // AnyTest is a TestSpec or ClientTestSpec.
 type AnyTest interface {
-	runTest(*Simulation, SuiteID, *Suite) error
+	runTest(*Simulation, testContext) error
+}
+
+// testContext describes where a test is started.
+type testContext struct {
+	suiteID SuiteID
+	suite   *Suite
+	parent  TestID          // zero for tests started by the suite
+	group   *sync.WaitGroup // parallel tests are added here
 }
 
 // Run executes all given test suites.
@@ -77,8 +86,13 @@ func RunSuite(host *Simulation, suite Suite) error {
 	}
 	defer host.EndSuite(suiteID)
 
+	// Parallel tests are waited for before ending the suite.
+	var parallel sync.WaitGroup
+	defer parallel.Wait()
+
 	for _, test := range suite.Tests {
-		if err := test.runTest(host, suiteID, &suite); err != nil {
+		ctx := testContext{suiteID: suiteID, suite: &suite, group: &parallel}
+		if err := test.runTest(host, ctx); err != nil {
 			return err
 		}
 	}
@@ -146,6 +160,10 @@ type ClientTestSpec struct {
 	// If no role is specified, the test runs for all available client types.
 	Role string
 
+	// This filters client types by features. The test runs only for
+	// clients which declare all of the given features.
+	Features []string
+
 	// Parameters and Files are launch options for client instances.
 	Parameters Params
 	Files      map[string]string
@@ -162,10 +180,25 @@ type Client struct {
 
 	mu        sync.Mutex
 	rpc       *rpc.Client
+	wsrpc     *rpc.Client
 	enginerpc *rpc.Client
+	meta      ClientMetadata
 	test      *T
 }
 
+// Port returns the TCP port with the given name, as declared in the client's hive.yaml.
+// For well-known port names, the default port is returned if the client doesn't
+// declare it. See the Port* constants.
+func (c *Client) Port(name string) (uint16, bool) {
+	return c.meta.Port(name)
+}
+
+// url returns the URL of the given port.
+func (c *Client) url(scheme, portName string) string {
+	port, _ := c.Port(portName)
+	return fmt.Sprintf("%s://%v:%d", scheme, c.IP, port)
+}
+
 // EnodeURL returns the default peer-to-peer endpoint of the client.
 func (c *Client) EnodeURL() (string, error) {
 	return c.test.Sim.ClientEnodeURL(c.test.SuiteID, c.test.TestID, c.Container)
@@ -181,11 +214,30 @@ func (c *Client) RPC() *rpc.Client {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.rpc == nil {
-		c.rpc, _ = rpc.DialHTTP(fmt.Sprintf("http://%v:8545", c.IP))
+		c.rpc, _ = rpc.DialHTTP(c.url("http", PortRPC))
 	}
 	return c.rpc
 }
 
+// WebSocket returns an RPC client connected to the client's WebSocket RPC server.
+func (c *Client) WebSocket() (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.wsrpc == nil {
+		ws, err := rpc.DialWebsocket(context.Background(), c.url("ws", PortWS), "")
+		if err != nil {
+			return nil, err
+		}
+		c.wsrpc = ws
+	}
+	return c.wsrpc, nil
+}
+
+// BeaconAPI returns the base URL of a consensus-layer client's beacon node HTTP API.
+func (c *Client) BeaconAPI() string {
+	return c.url("http", PortBeaconAPI)
+}
+
 // EngineAPI returns an RPC client connected to an execution-layer client's engine API server.
 func (c *Client) EngineAPI() *rpc.Client {
 	c.mu.Lock()
@@ -195,8 +247,7 @@ func (c *Client) EngineAPI() *rpc.Client {
 		return c.enginerpc
 	}
 	auth := rpc.WithHTTPAuth(jwtAuth(ENGINEAPI_JWT_SECRET))
-	url := fmt.Sprintf("http://%v:8551", c.IP)
-	c.enginerpc, _ = rpc.DialOptions(context.Background(), url, auth)
+	c.enginerpc, _ = rpc.DialOptions(context.Background(), c.url("http", PortEngine), auth)
 	return c.enginerpc
 }
 
@@ -227,6 +278,13 @@ type T struct {
 	suite   *Suite
 	mu      sync.Mutex
 	result  TestResult
+	cleanup []func()
+
+	// Parallel test state.
+	group    *sync.WaitGroup // parallel tests of the parent
+	signal   chan struct{}   // closed when the caller of runTest may continue
+	parallel bool
+	subtests sync.WaitGroup
 }
 
 // StartClient starts a client instance. If the client cannot by started, the test fails immediately.
@@ -235,15 +293,18 @@ func (t *T) StartClient(clientType string, option ...StartOption) *Client {
 	if err != nil {
 		t.Fatalf("can't launch node (type %s): %v", clientType, err)
 	}
-	return &Client{Type: clientType, Container: container, IP: ip, test: t}
+	client := &Client{Type: clientType, Container: container, IP: ip, test: t}
+	if def, err := t.Sim.clientDefinition(clientType); err == nil {
+		client.meta = def.Meta
+	}
+	return client
 }
 
 // RunClient runs the given client test against a single client type.
 // It waits for the subtest to complete.
 func (t *T) RunClient(clientType string, spec ClientTestSpec) {
 	test := testSpec{
-		suiteID:     t.SuiteID,
-		suite:       t.suite,
+		ctx:         t.subtestContext(),
 		name:        clientTestName(spec.Name, clientType),
 		displayName: spec.DisplayName,
 		category:    spec.Category,
@@ -257,16 +318,49 @@ func (t *T) RunClient(clientType string, spec ClientTestSpec) {
 }
 
 // RunAllClients runs the given client test against all available client types.
-// It waits for all subtests to complete.
+// It waits for all subtests to complete, unless they call t.Parallel.
 func (t *T) RunAllClients(spec ClientTestSpec) {
-	spec.runTest(t.Sim, t.SuiteID, t.suite)
+	spec.runTest(t.Sim, t.subtestContext())
 }
 
-// Run runs a subtest of this test. It waits for the subtest to complete before continuing.
-// It is safe to call this from multiple goroutines concurrently, just be sure to wait for
-// all your tests to finish until returning from the parent test.
+// Run runs a subtest of this test. It waits for the subtest to complete before continuing,
+// unless the subtest calls t.Parallel. It is safe to call this from multiple goroutines
+// concurrently, just be sure to wait for all your tests to finish until returning from
+// the parent test.
 func (t *T) Run(spec TestSpec) {
-	spec.runTest(t.Sim, t.SuiteID, t.suite)
+	spec.runTest(t.Sim, t.subtestContext())
+}
+
+// subtestContext returns the context of tests started by t.
+func (t *T) subtestContext() testContext {
+	return testContext{suiteID: t.SuiteID, suite: t.suite, parent: t.TestID, group: &t.subtests}
+}
+
+// Parallel signals that this test is to be run in parallel with other parallel tests.
+// The call returns control to the parent test, and then blocks until the test may run.
+// The number of parallel tests running at the same time is limited by HIVE_PARALLELISM.
+// A test does not end until all of its parallel subtests have ended.
+//
+// Like testing.T.Parallel, this must be called from the test's goroutine.
+func (t *T) Parallel() {
+	if t.parallel {
+		panic("hivesim: t.Parallel called multiple times")
+	}
+	t.parallel = true
+	t.group.Add(1)
+	close(t.signal)
+	t.Sim.sched.acquire()
+}
+
+// Attach stores the content of data as a file attached to the test result. The file
+// can be downloaded from the test details in hiveview. The name must be a file name
+// without directory components, and must be unique within the test.
+//
+// Errors are reported to the test log, but do not fail the test.
+func (t *T) Attach(name string, data io.Reader) {
+	if err := t.Sim.AttachArtifact(t.SuiteID, t.TestID, name, data); err != nil {
+		t.Logf("can't attach %s: %v", name, err)
+	}
 }
 
 // Error is like testing.T.Error.
@@ -333,9 +427,79 @@ func (t *T) FailNow() {
 	runtime.Goexit()
 }
 
+// Cleanup registers a function to be called when the test and all its subtests complete.
+// Cleanup functions are called in last added, first called order, before hive stops the
+// clients of the test. They also run when the test function panics or calls FailNow.
+func (t *T) Cleanup(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cleanup = append(t.cleanup, f)
+}
+
+// runCleanup calls the registered cleanup functions. Panics in cleanup functions
+// are reported as test failures.
+func (t *T) runCleanup() {
+	for {
+		t.mu.Lock()
+		n := len(t.cleanup)
+		if n == 0 {
+			t.mu.Unlock()
+			return
+		}
+		f := t.cleanup[n-1]
+		t.cleanup = t.cleanup[:n-1]
+		t.mu.Unlock()
+
+		// The function runs on its own goroutine, so a call to
+		// FailNow does not abort the remaining cleanup.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() {
+				if err := recover(); err != nil {
+					buf := make([]byte, 4096)
+					i := runtime.Stack(buf, false)
+					t.Logf("panic in cleanup: %v\n\n%s", err, buf[:i])
+					t.Fail()
+				}
+			}()
+			f()
+		}()
+		<-done
+	}
+}
+
+// Skip is like testing.T.Skip. It logs the values and marks the test as skipped.
+func (t *T) Skip(values ...interface{}) {
+	t.Log(values...)
+	t.SkipNow()
+}
+
+// Skipf is like testing.T.Skipf. It logs the message and marks the test as skipped.
+func (t *T) Skipf(format string, values ...interface{}) {
+	t.Logf(format, values...)
+	t.SkipNow()
+}
+
+// SkipNow marks the test as skipped and exits the test immediately. A test which has
+// already failed is still reported as failed. As with FailNow, this should only be
+// called from the main test goroutine.
+func (t *T) SkipNow() {
+	t.mu.Lock()
+	t.result.Skipped = true
+	t.mu.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped reports whether the test was skipped.
+func (t *T) Skipped() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.result.Skipped
+}
+
 type testSpec struct {
-	suiteID     SuiteID
-	suite       *Suite
+	ctx         testContext
 	name        string
 	displayName string
 	category    string
@@ -349,11 +513,12 @@ func (spec testSpec) request() *simapi.TestRequest {
 		DisplayName: spec.displayName,
 		Category:    spec.category,
 		Description: spec.desc,
+		Parent:      uint32(spec.ctx.parent),
 	}
 }
 
 func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
-	if !test.alwaysRun && !host.m.match(test.suite.Name, test.name) {
+	if !test.alwaysRun && !host.m.match(test.ctx.suite.Name, test.name) {
 		if host.ll > 3 { // hive log level > 3
 			fmt.Fprintf(os.Stderr, "skipping test %q because it doesn't match test pattern %s\n", test.name, host.m.pattern)
 		}
@@ -363,24 +528,21 @@ func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
 	// Register test on simulation server and initialize the T.
 	t := &T{
 		Sim:     host,
-		SuiteID: test.suiteID,
-		suite:   test.suite,
+		SuiteID: test.ctx.suiteID,
+		suite:   test.ctx.suite,
+		group:   test.ctx.group,
+		signal:  make(chan struct{}),
 	}
-	testID, err := host.StartTest(test.suiteID, test.request())
+	testID, err := host.StartTest(test.ctx.suiteID, test.request())
 	if err != nil {
 		return err
 	}
 	t.TestID = testID
 	t.result.Pass = true
-	defer func() {
-		t.mu.Lock()
-		defer t.mu.Unlock()
-		host.EndTest(test.suiteID, testID, t.result)
-	}()
 
 	// Run the test function.
-	done := make(chan struct{})
 	go func() {
+		defer t.finish()
 		defer func() {
 			if err := recover(); err != nil {
 				buf := make([]byte, 4096)
@@ -388,19 +550,42 @@ func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
 				t.Logf("panic: %v\n\n%s", err, buf[:i])
 				t.Fail()
 			}
-			close(done)
 		}()
 		if host.CollectTestsOnly() && !test.alwaysRun {
-			// Don't run the test if we're just generating docs.
+			// Don't run the test if we're just generating docs or listing tests.
 			return
 		}
 		runit(t)
 	}()
-	<-done
+	// Wait until the test has finished or called t.Parallel.
+	<-t.signal
 	return nil
 }
 
-func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite) error {
+// finish waits for parallel subtests, runs cleanup functions and reports the test result.
+func (t *T) finish() {
+	if t.parallel {
+		// Give up the slot while waiting, subtests may need it.
+		t.Sim.sched.release()
+	}
+	t.subtests.Wait()
+	t.runCleanup()
+
+	t.mu.Lock()
+	if !t.result.Pass {
+		t.result.Skipped = false
+	}
+	t.Sim.EndTest(t.SuiteID, t.TestID, t.result)
+	t.mu.Unlock()
+
+	if t.parallel {
+		t.group.Done()
+	} else {
+		close(t.signal)
+	}
+}
+
+func (spec ClientTestSpec) runTest(host *Simulation, ctx testContext) error {
 	clients, err := host.ClientTypes()
 	if err != nil {
 		return err
@@ -411,9 +596,11 @@ func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Sui
 		if spec.Role != "" && !clientDef.HasRole(spec.Role) {
 			continue
 		}
+		if !hasFeatures(clientDef, spec.Features) {
+			continue
+		}
 		test := testSpec{
-			suiteID:     suiteID,
-			suite:       suite,
+			ctx:         ctx,
 			name:        clientTestName(spec.Name, clientDef.Name),
 			displayName: spec.DisplayName,
 			category:    spec.Category,
@@ -431,6 +618,16 @@ func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Sui
 	return nil
 }
 
+// hasFeatures reports whether the client has all of the given features.
+func hasFeatures(clientDef *ClientDefinition, features []string) bool {
+	for _, f := range features {
+		if !clientDef.HasFeature(f) {
+			return false
+		}
+	}
+	return true
+}
+
 // clientTestName ensures that 'name' contains the client type.
 func clientTestName(name, clientType string) string {
 	if name == "" {
@@ -442,10 +639,9 @@ func clientTestName(name, clientType string) string {
 	return name + " (" + clientType + ")"
 }
 
-func (spec TestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite) error {
+func (spec TestSpec) runTest(host *Simulation, ctx testContext) error {
 	test := testSpec{
-		suiteID:     suiteID,
-		suite:       suite,
+		ctx:         ctx,
 		name:        spec.Name,
 		displayName: spec.DisplayName,
 		category:    spec.Category,
@@ -1,12 +1,19 @@
 package hivesim
 
 import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/hive/internal/fakes"
 	"github.com/ethereum/hive/internal/libhive"
 )
 
@@ -152,3 +159,383 @@ func TestSkipping(t *testing.T) {
 		}
 	}
 }
+
+// This test checks that parallel tests respect the parallelism limit and that
+// parents wait for their parallel subtests.
+func TestParallel(t *testing.T) {
+	const limit = 3
+	var (
+		mu          sync.Mutex
+		running     int
+		maxRunning  int
+		parentEnded bool
+		subsEnded   int
+	)
+	enter := func() {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+	}
+	leave := func() {
+		mu.Lock()
+		running--
+		subsEnded++
+		mu.Unlock()
+	}
+
+	suite := Suite{Name: "parallel"}
+	suite.Add(TestSpec{
+		Name: "parent",
+		Run: func(t *T) {
+			t.Parallel()
+			for i := 0; i < 10; i++ {
+				t.Run(TestSpec{
+					Name: fmt.Sprintf("sub-%d", i),
+					Run: func(t *T) {
+						t.Parallel()
+						enter()
+						defer leave()
+						time.Sleep(20 * time.Millisecond)
+						if t.TestID == 5 {
+							t.Fatal("failing subtest")
+						}
+					},
+				})
+			}
+			mu.Lock()
+			parentEnded = subsEnded == 10
+			mu.Unlock()
+		},
+	})
+	suite.Add(TestSpec{Name: "sequential", Run: func(t *T) {}})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	sim := NewAt(srv.URL)
+	sim.SetParallelism(limit)
+	if err := RunSuite(sim, suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if maxRunning > limit {
+		t.Errorf("%d tests running at the same time, limit is %d", maxRunning, limit)
+	}
+	if maxRunning < 2 {
+		t.Errorf("subtests did not run in parallel")
+	}
+	if parentEnded {
+		t.Errorf("subtests ended before Run returned")
+	}
+
+	results := tm.Results()
+	if len(results) != 1 {
+		t.Fatalf("wrong number of suites: %d", len(results))
+	}
+	var (
+		passes, fails int
+		parent        *libhive.TestCase
+		parentID      libhive.TestID
+		lastSubEnd    time.Time
+	)
+	for id, test := range results[0].TestCases {
+		if test.Name == "parent" {
+			parent, parentID = test, id
+		}
+	}
+	for _, test := range results[0].TestCases {
+		if test.End.IsZero() {
+			t.Errorf("test %q was not ended", test.Name)
+		}
+		if strings.HasPrefix(test.Name, "sub-") {
+			if test.End.After(lastSubEnd) {
+				lastSubEnd = test.End
+			}
+			if test.Parent != parentID {
+				t.Errorf("test %q has parent %d, want %d", test.Name, test.Parent, parentID)
+			}
+		} else if test.Parent != 0 {
+			t.Errorf("top-level test %q has parent %d", test.Name, test.Parent)
+		}
+		if test.SummaryResult.Pass {
+			passes++
+		} else {
+			fails++
+		}
+	}
+	if passes != 11 || fails != 1 {
+		t.Errorf("wrong results: %d passed, %d failed", passes, fails)
+	}
+	if parent == nil || parent.End.Before(lastSubEnd) {
+		t.Errorf("parent test ended before its subtests")
+	}
+}
+
+// This test checks that skipped tests are reported.
+func TestSkip(t *testing.T) {
+	suite := Suite{Name: "skip"}
+	suite.Add(TestSpec{
+		Name: "skipped",
+		Run: func(t *T) {
+			t.Skipf("feature %s not supported", "x")
+			t.Fatal("test continued after Skip")
+		},
+	})
+	suite.Add(TestSpec{
+		Name: "failed-then-skipped",
+		Run: func(t *T) {
+			t.Fail()
+			t.Skip("skip")
+		},
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+	results := tm.Results()
+	removeTimestamps(results)
+
+	wantResults := map[libhive.TestID]*libhive.TestCase{
+		1: {
+			Name:          "skipped",
+			SummaryResult: libhive.TestResult{Pass: true, Skipped: true, Details: "feature x not supported\n"},
+		},
+		2: {
+			Name:          "failed-then-skipped",
+			SummaryResult: libhive.TestResult{Pass: false, Details: "skip\n"},
+		},
+	}
+	if !reflect.DeepEqual(results[0].TestCases, wantResults) {
+		t.Fatal("wrong results reported:", spew.Sdump(results[0].TestCases))
+	}
+}
+
+// This test checks that cleanup functions run in LIFO order before the clients
+// of the test are removed, even when the test exits through FailNow.
+func TestCleanup(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		order = append(order, s)
+		mu.Unlock()
+	}
+	hooks := &fakes.BackendHooks{
+		DeleteContainer: func(containerID string) error {
+			record("delete-client")
+			return nil
+		},
+	}
+
+	suite := Suite{Name: "cleanup"}
+	suite.Add(TestSpec{
+		Name: "test",
+		Run: func(t *T) {
+			t.StartClient("client-1")
+			t.Cleanup(func() { record("cleanup-1") })
+			t.Cleanup(func() {
+				record("cleanup-2")
+				panic("cleanup failed")
+			})
+			t.Cleanup(func() {
+				record("cleanup-3")
+				t.FailNow()
+			})
+			t.Fatal("test failed")
+		},
+	})
+
+	tm, srv := newFakeAPI(hooks)
+	defer srv.Close()
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	want := []string{"cleanup-3", "cleanup-2", "cleanup-1", "delete-client"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("wrong execution order %v, want %v", order, want)
+	}
+	result := tm.Results()[0].TestCases[1].SummaryResult
+	if result.Pass {
+		t.Error("test passed")
+	}
+	if !strings.Contains(result.Details, "panic in cleanup: cleanup failed") {
+		t.Errorf("cleanup panic not reported in details: %q", result.Details)
+	}
+}
+
+// This test checks that artifacts attached to a test are stored in the
+// results directory.
+func TestAttach(t *testing.T) {
+	env := libhive.SimEnv{LogDir: t.TempDir(), ArtifactSizeLimit: 10}
+	tm := libhive.NewTestManager(env, fakes.NewContainerBackend(nil), nil)
+	srv := httptest.NewServer(tm.API())
+	defer srv.Close()
+
+	suite := Suite{Name: "artifacts"}
+	suite.Add(TestSpec{
+		Name: "test",
+		Run: func(t *T) {
+			t.Attach("payload.json", strings.NewReader(`{"a":1}`))
+			t.Attach("payload.json", strings.NewReader("duplicate"))
+			t.Attach("too-large.txt", strings.NewReader("more than ten bytes"))
+			t.Attach("../escape", strings.NewReader("x"))
+		},
+	})
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	result := tm.Results()[0]
+	test := result.TestCases[1]
+	if len(test.Artifacts) != 1 {
+		t.Fatalf("wrong number of artifacts: %d", len(test.Artifacts))
+	}
+	a := test.Artifacts[0]
+	if a.Name != "payload.json" || a.Size != 7 || !strings.HasPrefix(a.File, libhive.ArtifactsDir+"/") {
+		t.Fatalf("wrong artifact %+v", a)
+	}
+	content, err := os.ReadFile(filepath.Join(env.LogDir, filepath.FromSlash(a.File)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != `{"a":1}` {
+		t.Fatalf("wrong artifact content %q", content)
+	}
+	// The errors should be reported in the test log.
+	details, err := os.ReadFile(filepath.Join(env.LogDir, result.TestDetailsLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"already exists", "size limit exceeded", "can't attach ../escape"} {
+		if !strings.Contains(string(details), msg) {
+			t.Errorf("error %q not reported in details: %q", msg, details)
+		}
+	}
+	if !test.SummaryResult.Pass {
+		t.Error("test failed")
+	}
+	// The rejected files should not be present.
+	files, _ := filepath.Glob(filepath.Join(env.LogDir, libhive.ArtifactsDir, "*", "*"))
+	if len(files) != 1 {
+		t.Errorf("wrong files in artifacts directory: %v", files)
+	}
+}
+
+func TestClientPorts(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		checkLive = make(map[string]uint16)
+	)
+	hooks := &fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			mu.Lock()
+			checkLive[image] = opt.CheckLive
+			mu.Unlock()
+			return &libhive.ContainerInfo{}, nil
+		},
+	}
+
+	var tested []string
+	suite := Suite{Name: "ports"}
+	suite.Add(ClientTestSpec{
+		Name:     "CLIENT",
+		Features: []string{"light-client"},
+		Run: func(t *T, c *Client) {
+			tested = append(tested, c.Type)
+			if port, ok := c.Port(PortBeaconAPI); !ok || port != 5052 {
+				t.Errorf("wrong beacon-api port %d (ok=%t)", port, ok)
+			}
+			if url := c.BeaconAPI(); url != fmt.Sprintf("http://%v:5052", c.IP) {
+				t.Errorf("wrong beacon API URL %q", url)
+			}
+			if _, ok := c.Port(PortMetrics); ok {
+				t.Error("undeclared metrics port found")
+			}
+		},
+	})
+	suite.Add(TestSpec{
+		Name: "defaults",
+		Run: func(t *T) {
+			c := t.StartClient("client-1")
+			if port, ok := c.Port(PortRPC); !ok || port != 8545 {
+				t.Errorf("wrong default rpc port %d (ok=%t)", port, ok)
+			}
+		},
+	})
+
+	tm, srv := newFakeAPI(hooks)
+	defer srv.Close()
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if !reflect.DeepEqual(tested, []string{"client-2"}) {
+		t.Errorf("wrong clients tested: %v", tested)
+	}
+	for _, tc := range tm.Results()[0].TestCases {
+		if !tc.SummaryResult.Pass {
+			t.Errorf("test %q failed: %s", tc.Name, tc.SummaryResult.Details)
+		}
+	}
+	wantCheckLive := map[string]uint16{"/not/exposed/": 5052, "/ignored/in/api": 8545}
+	if !reflect.DeepEqual(checkLive, wantCheckLive) {
+		t.Errorf("wrong check-live ports %v, want %v", checkLive, wantCheckLive)
+	}
+}
+
+func TestListMode(t *testing.T) {
+	var ran []string
+	suite := Suite{Name: "list"}
+	suite.Add(TestSpec{
+		Name: "test",
+		Run:  func(t *T) { ran = append(ran, "test") },
+	})
+	suite.Add(TestSpec{
+		Name:      "always",
+		AlwaysRun: true,
+		Run:       func(t *T) { ran = append(ran, "always") },
+	})
+	suite.Add(TestSpec{
+		Name: "filtered",
+		Run:  func(t *T) { ran = append(ran, "filtered") },
+	})
+	suite.Add(ClientTestSpec{
+		Name: "CLIENT test",
+		Run:  func(t *T, c *Client) { ran = append(ran, c.Type) },
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	sim := NewAt(srv.URL)
+	sim.listTests = true
+	sim.SetTestPattern("list/test|always")
+	if err := RunSuite(sim, suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if !reflect.DeepEqual(ran, []string{"always"}) {
+		t.Errorf("wrong tests run in list mode: %v, want only AlwaysRun test", ran)
+	}
+	var names []string
+	for _, tc := range tm.Results()[0].TestCases {
+		names = append(names, tc.Name)
+	}
+	sort.Strings(names)
+	want := []string{"always", "client-1 test", "client-2 test", "test"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("wrong tests listed: %q, want %q", names, want)
+	}
+}
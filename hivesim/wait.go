@@ -0,0 +1,155 @@
+package hivesim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultPollInterval is the interval used by the WaitFor* methods of T.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// WaitFor calls cond every interval until it returns nil, or until ctx is done.
+//
+// The error returned by cond should describe why the condition isn't met yet, e.g.
+// "peer not connected". When ctx is done before the condition is met, WaitFor returns
+// an error containing a summary of all attempts, where consecutive attempts with the
+// same error message are merged into one line.
+func WaitFor(ctx context.Context, interval time.Duration, cond func() error) error {
+	var (
+		start    = time.Now()
+		attempts attemptLog
+		timer    = time.NewTimer(0)
+	)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if attempts.count == 0 {
+				return ctx.Err()
+			}
+			return fmt.Errorf("condition not met after %v (%d attempts):\n%s", time.Since(start).Round(time.Millisecond), attempts.count, attempts)
+		case <-timer.C:
+		}
+		err := cond()
+		if err == nil {
+			return nil
+		}
+		attempts.add(time.Since(start), err)
+		timer.Reset(interval)
+	}
+}
+
+// Eventually calls cond every interval until it returns nil. If ctx is done before the
+// condition is met, the test fails and the attempts are logged. See WaitFor for more
+// information.
+func (t *T) Eventually(ctx context.Context, interval time.Duration, cond func() error) {
+	if err := WaitFor(ctx, interval, cond); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// WaitForBlock waits until the head block of the client reaches the given number.
+func (t *T) WaitForBlock(ctx context.Context, c *Client, number uint64) {
+	t.Eventually(ctx, DefaultPollInterval, func() error {
+		var head hexutil.Uint64
+		if err := c.RPC().CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+			return err
+		}
+		if uint64(head) < number {
+			return progressf("head", "head is %d, want %d", head, number)
+		}
+		return nil
+	})
+}
+
+// WaitForPeers waits until the client is connected to at least n peers.
+func (t *T) WaitForPeers(ctx context.Context, c *Client, n int) {
+	t.Eventually(ctx, DefaultPollInterval, func() error {
+		var count hexutil.Uint
+		if err := c.RPC().CallContext(ctx, &count, "net_peerCount"); err != nil {
+			return err
+		}
+		if int(count) < n {
+			return progressf("peers", "%d peers, want %d", count, n)
+		}
+		return nil
+	})
+}
+
+// WaitForSyncComplete waits until the client reports that it is not syncing.
+func (t *T) WaitForSyncComplete(ctx context.Context, c *Client) {
+	t.Eventually(ctx, DefaultPollInterval, func() error {
+		var progress any
+		if err := c.RPC().CallContext(ctx, &progress, "eth_syncing"); err != nil {
+			return err
+		}
+		if progress, ok := progress.(map[string]any); ok {
+			return progressf("syncing", "syncing: current block %v, highest block %v", progress["currentBlock"], progress["highestBlock"])
+		}
+		if progress != false {
+			return errors.New("unexpected eth_syncing result")
+		}
+		return nil
+	})
+}
+
+// progressError is returned by the conditions of the WaitFor* methods when the client
+// makes progress. Its message changes on every attempt, so consecutive attempts are
+// merged by kind instead, keeping the message of the latest one.
+type progressError struct {
+	kind string
+	msg  string
+}
+
+func progressf(kind, format string, args ...any) error {
+	return &progressError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+func (err *progressError) Error() string {
+	return err.msg
+}
+
+// attemptLog records the outcomes of WaitFor attempts.
+type attemptLog struct {
+	count   int
+	entries []attemptEntry
+}
+
+type attemptEntry struct {
+	first, last int
+	start, end  time.Duration
+	key, msg    string
+}
+
+func (l *attemptLog) add(elapsed time.Duration, err error) {
+	l.count++
+	msg, key := err.Error(), err.Error()
+	if perr, ok := err.(*progressError); ok {
+		key = "progress: " + perr.kind
+	}
+	if n := len(l.entries); n > 0 && l.entries[n-1].key == key {
+		l.entries[n-1].last = l.count
+		l.entries[n-1].end = elapsed
+		l.entries[n-1].msg = msg
+		return
+	}
+	l.entries = append(l.entries, attemptEntry{first: l.count, last: l.count, start: elapsed, end: elapsed, key: key, msg: msg})
+}
+
+func (l attemptLog) String() string {
+	var b strings.Builder
+	for _, e := range l.entries {
+		start, end := e.start.Round(time.Millisecond), e.end.Round(time.Millisecond)
+		if e.first == e.last {
+			fmt.Fprintf(&b, "  #%d (%v): %s\n", e.first, start, e.msg)
+		} else {
+			fmt.Fprintf(&b, "  #%d-#%d (%v-%v): %s\n", e.first, e.last, start, end, e.msg)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
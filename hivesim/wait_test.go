@@ -0,0 +1,149 @@
+package hivesim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/hive/internal/fakes"
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+func TestWaitFor(t *testing.T) {
+	var calls int
+	err := WaitFor(context.Background(), time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("wrong number of calls %d", calls)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var calls int
+	err := WaitFor(ctx, 10*time.Millisecond, func() error {
+		calls++
+		if calls <= 2 {
+			return errors.New("connection refused")
+		}
+		return progressf("head", "head is %d, want 100", calls)
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	msg := err.Error()
+	lines := strings.Split(msg, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("wrong number of lines in error:\n%s", msg)
+	}
+	if !strings.HasPrefix(lines[1], "  #1-#2 (") || !strings.HasSuffix(lines[1], "): connection refused") {
+		t.Errorf("wrong first entry %q", lines[1])
+	}
+	// Progress is merged into one entry, which shows the latest head.
+	last := strings.TrimPrefix(strings.Fields(lines[2])[0], "#3-#")
+	if !strings.HasPrefix(lines[2], "  #3-#") || !strings.HasSuffix(lines[2], fmt.Sprintf("): head is %s, want 100", last)) {
+		t.Errorf("wrong second entry %q", lines[2])
+	}
+}
+
+type waitTestService struct {
+	head uint64
+}
+
+func (s *waitTestService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(atomic.AddUint64(&s.head, 1))
+}
+
+func (s *waitTestService) Syncing() any {
+	if head := atomic.LoadUint64(&s.head); head < 3 {
+		return map[string]any{"currentBlock": hexutil.Uint64(head), "highestBlock": "0x3"}
+	}
+	return false
+}
+
+type waitTestNetService struct{}
+
+func (waitTestNetService) PeerCount() hexutil.Uint { return 2 }
+
+func TestWaitForClient(t *testing.T) {
+	// Start the client RPC server.
+	rpcsrv := rpc.NewServer()
+	rpcsrv.RegisterName("eth", new(waitTestService))
+	rpcsrv.RegisterName("net", waitTestNetService{})
+	httpsrv := httptest.NewServer(rpcsrv)
+	defer httpsrv.Close()
+	_, port, _ := net.SplitHostPort(httpsrv.Listener.Addr().String())
+	var rpcPort uint16
+	fmt.Sscan(port, &rpcPort)
+
+	// Create the API with a client using the server.
+	hooks := &fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			return &libhive.ContainerInfo{IP: "127.0.0.1"}, nil
+		},
+	}
+	defs := []*libhive.ClientDefinition{
+		{Name: "client", Image: "client", Meta: libhive.ClientMetadata{Ports: map[string]uint16{"rpc": rpcPort}}},
+	}
+	tm := libhive.NewTestManager(libhive.SimEnv{}, fakes.NewContainerBackend(hooks), defs)
+	srv := httptest.NewServer(tm.API())
+	defer srv.Close()
+
+	suite := Suite{Name: "wait"}
+	suite.Add(ClientTestSpec{
+		Name: "CLIENT",
+		Run: func(t *T, c *Client) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			t.WaitForBlock(ctx, c, 3)
+			t.WaitForPeers(ctx, c, 2)
+			t.WaitForSyncComplete(ctx, c)
+		},
+	})
+	suite.Add(ClientTestSpec{
+		Name: "CLIENT timeout",
+		Run: func(t *T, c *Client) {
+			ctx, cancel := context.WithTimeout(context.Background(), 1200*time.Millisecond)
+			defer cancel()
+			t.WaitForPeers(ctx, c, 5)
+		},
+	})
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	for _, tc := range tm.Results()[0].TestCases {
+		switch tc.Name {
+		case "client":
+			if !tc.SummaryResult.Pass {
+				t.Errorf("test failed: %s", tc.SummaryResult.Details)
+			}
+		case "client timeout":
+			if tc.SummaryResult.Pass {
+				t.Error("timeout test passed")
+			}
+			if !strings.Contains(tc.SummaryResult.Details, ": 2 peers, want 5") {
+				t.Errorf("attempts not logged: %s", tc.SummaryResult.Details)
+			}
+		}
+	}
+}
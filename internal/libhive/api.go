@@ -42,6 +42,7 @@ func newSimulationAPI(b ContainerBackend, env SimEnv, tm *TestManager) http.Hand
 	router.HandleFunc("/testsuite/{suite}/test", api.startTest).Methods("POST")
 	// post because the delete http verb does not always support a message body
 	router.HandleFunc("/testsuite/{suite}/test/{test}", api.endTest).Methods("POST")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/artifact/{name}", api.addArtifact).Methods("POST")
 	router.HandleFunc("/testsuite", api.startSuite).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}", api.endSuite).Methods("DELETE")
 	router.HandleFunc("/testsuite/{suite}/network/{network}", api.networkCreate).Methods("POST")
@@ -49,6 +50,7 @@ func newSimulationAPI(b ContainerBackend, env SimEnv, tm *TestManager) http.Hand
 	router.HandleFunc("/testsuite/{suite}/network/{network}/{node}", api.networkIPGet).Methods("GET")
 	router.HandleFunc("/testsuite/{suite}/network/{network}/{node}", api.networkConnect).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/network/{network}/{node}", api.networkDisconnect).Methods("DELETE")
+	router.Use(apiMetricsMiddleware)
 	return router
 }
 
@@ -117,7 +119,7 @@ func (api *simAPI) startTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	testID, err := api.tm.StartTest(suiteID, test.Name, test.Description)
+	testID, err := api.tm.StartTest(suiteID, test.Name, test.Description, TestID(test.Parent))
 	if err != nil {
 		err := fmt.Errorf("can't start test case: %s", err.Error())
 		serveError(w, err, http.StatusInternalServerError)
@@ -156,6 +158,31 @@ func (api *simAPI) endTest(w http.ResponseWriter, r *http.Request) {
 	serveOK(w)
 }
 
+// addArtifact stores the request body as a file attached to a test.
+func (api *simAPI) addArtifact(w http.ResponseWriter, r *http.Request) {
+	suiteID, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	artifact, err := api.tm.AddArtifact(suiteID, testID, name, r.Body)
+	switch {
+	case err == ErrNoSuchTestSuite || err == ErrNoSuchTestCase:
+		serveError(w, err, http.StatusNotFound)
+		return
+	case err == ErrArtifactTooLarge:
+		serveError(w, err, http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		log15.Error("API: can't store artifact", "suite", suiteID, "test", testID, "name", name, "error", err)
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	log15.Info("API: artifact added", "suite", suiteID, "test", testID, "name", name, "size", artifact.Size)
+	serveJSON(w, artifact)
+}
+
 // startClient starts a client container.
 func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	suiteID, testID, err := api.requestSuiteAndTest(r)
@@ -262,8 +289,8 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// by default: check the eth1 port
-	options.CheckLive = 8545
+	// By default, check the port declared by the client.
+	options.CheckLive = clientDef.Meta.checkLivePort()
 	if portStr := env["HIVE_CHECK_LIVE_PORT"]; portStr != "" {
 		v, err := strconv.ParseUint(portStr, 10, 16)
 		if err != nil {
@@ -275,7 +302,9 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Start it!
+	startTime := time.Now()
 	info, err := api.backend.StartContainer(ctx, containerID, options)
+	recordClientStart(clientDef.Name, startTime, err)
 	if info != nil {
 		clientInfo := &ClientInfo{
 			ID:             info.ID,
@@ -306,13 +335,21 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 
 	// It's started.
 	log15.Info("API: client "+clientDef.Name+" started", "suite", suiteID, "test", testID, "container", containerID[:8])
+	if nodeInfo, err := api.tm.GetNodeInfo(suiteID, testID, info.ID); err == nil {
+		api.tm.publish(Event{Type: EventClientStarted, Suite: suiteID, Test: testID, Client: nodeInfo})
+	}
 	serveJSON(w, &simapi.StartNodeResponse{ID: info.ID, IP: info.IP})
 }
 
 // clientLogFilePaths determines the log file path of a client container.
 // Note that jsonPath gets written to the result JSON and always uses '/' as the separator.
 // The filePath is passed to the docker backend and uses the platform separator.
+// Both paths are empty when there is no log directory, and the client output is
+// discarded in that case.
 func (api *simAPI) clientLogFilePaths(clientName, containerID string) (jsonPath string, file string) {
+	if api.env.LogDir == "" {
+		return "", ""
+	}
 	// TODO: might be nice to put timestamp into the filename as well.
 	safeDir := strings.Replace(clientName, string(filepath.Separator), "_", -1)
 	jsonPath = path.Join(safeDir, fmt.Sprintf("client-%s.log", containerID))
@@ -0,0 +1,137 @@
+package libhive
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArtifactsDir is the directory containing test artifacts, relative to the log directory.
+const ArtifactsDir = "artifacts"
+
+// DefaultArtifactSizeLimit is the default limit for the total size of artifacts of a test.
+const DefaultArtifactSizeLimit = 64 * 1024 * 1024
+
+var (
+	ErrArtifactTooLarge    = errors.New("artifact size limit exceeded")
+	ErrInvalidArtifactName = errors.New("invalid artifact name")
+)
+
+// Artifact is a file attached to a test case.
+type Artifact struct {
+	Name string `json:"name"`
+	File string `json:"file"` // path of the file, relative to the log directory
+	Size int64  `json:"size"`
+}
+
+// checkArtifactName validates the file name of an artifact.
+func checkArtifactName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return ErrInvalidArtifactName
+	}
+	return nil
+}
+
+// AddArtifact stores the content of r as an artifact of the given test. When there is
+// no log directory, the content is discarded and the artifact isn't recorded.
+func (manager *TestManager) AddArtifact(suiteID TestSuiteID, testID TestID, name string, r io.Reader) (*Artifact, error) {
+	if err := checkArtifactName(name); err != nil {
+		return nil, err
+	}
+
+	// Check the test and determine how much space is left.
+	manager.testCaseMutex.Lock()
+	if _, ok := manager.runningTestSuites[suiteID]; !ok {
+		manager.testCaseMutex.Unlock()
+		return nil, ErrNoSuchTestSuite
+	}
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		manager.testCaseMutex.Unlock()
+		return nil, ErrNoSuchTestCase
+	}
+	if manager.config.LogDir == "" {
+		manager.testCaseMutex.Unlock()
+		size, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, err
+		}
+		return &Artifact{Name: name, Size: size}, nil
+	}
+	if testCase.artifactDir == "" {
+		testCase.artifactDir = path.Join(ArtifactsDir, newArtifactDirName(testID))
+	}
+	remaining := manager.artifactSizeLimit() - testCase.artifactSize
+	jsonPath := path.Join(testCase.artifactDir, name)
+	manager.testCaseMutex.Unlock()
+
+	// Write the file. The reader is limited to one byte more than allowed,
+	// in order to detect when the limit is exceeded.
+	file := filepath.Join(manager.config.LogDir, filepath.FromSlash(jsonPath))
+	size, err := writeArtifactFile(file, io.LimitReader(r, remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > remaining {
+		os.Remove(file)
+		return nil, ErrArtifactTooLarge
+	}
+
+	// Add it to the test case. The size is checked again here because
+	// other artifacts may have been added concurrently.
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+	if testCase.artifactSize+size > manager.artifactSizeLimit() {
+		os.Remove(file)
+		return nil, ErrArtifactTooLarge
+	}
+	artifact := &Artifact{Name: name, File: jsonPath, Size: size}
+	testCase.artifactSize += size
+	testCase.Artifacts = append(testCase.Artifacts, artifact)
+	return artifact, nil
+}
+
+func (manager *TestManager) artifactSizeLimit() int64 {
+	if manager.config.ArtifactSizeLimit > 0 {
+		return manager.config.ArtifactSizeLimit
+	}
+	return DefaultArtifactSizeLimit
+}
+
+// writeArtifactFile creates file and writes the content of r to it.
+// It fails if the file already exists.
+func writeArtifactFile(file string, r io.Reader) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return 0, fmt.Errorf("artifact %s already exists", filepath.Base(file))
+		}
+		return 0, err
+	}
+	size, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+		return 0, err
+	}
+	return size, nil
+}
+
+// newArtifactDirName creates a unique directory name for the artifacts of a test.
+// Like suite files, the name starts with a timestamp to make cleanups easier.
+func newArtifactDirName(testID TestID) string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return fmt.Sprintf("%d-%x-%d", time.Now().Unix(), b, testID)
+}
@@ -2,6 +2,7 @@ package libhive
 
 import (
 	"os"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -35,20 +36,36 @@ type TestSuite struct {
 	testLogOffset   int64
 }
 
+// ClientNames returns the sorted names of all clients started by the suite.
+func (s *TestSuite) ClientNames() []string {
+	names := make([]string, 0, len(s.ClientVersions))
+	for name := range s.ClientVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // TestCase represents a single test case in a test suite.
 type TestCase struct {
-	Name          string                 `json:"name"`        // Test case short name.
-	Description   string                 `json:"description"` // Test case long description in MD.
+	Name          string                 `json:"name"`             // Test case short name.
+	Description   string                 `json:"description"`      // Test case long description in MD.
+	Parent        TestID                 `json:"parent,omitempty"` // ID of the parent test case, zero for top-level tests.
 	Start         time.Time              `json:"start"`
 	End           time.Time              `json:"end"`
-	SummaryResult TestResult             `json:"summaryResult"` // The result of the whole test case.
-	ClientInfo    map[string]*ClientInfo `json:"clientInfo"`    // Info about each client.
+	SummaryResult TestResult             `json:"summaryResult"`       // The result of the whole test case.
+	ClientInfo    map[string]*ClientInfo `json:"clientInfo"`          // Info about each client.
+	Artifacts     []*Artifact            `json:"artifacts,omitempty"` // Files attached to the test.
+
+	artifactDir  string
+	artifactSize int64
 }
 
 // TestResult represents the result of a test case.
 type TestResult struct {
 	Pass    bool `json:"pass"`
 	Timeout bool `json:"timeout,omitempty"`
+	Skipped bool `json:"skipped,omitempty"` // skipped tests are not failures, Pass is also set
 
 	// The test log can be stored inline ("details"), or as offsets into the
 	// suite's TestDetailsLog file ("log").
@@ -8,6 +8,8 @@ import (
 	"mime/multipart"
 	"net"
 	"net/http"
+
+	"github.com/ethereum/hive/internal/simapi"
 )
 
 // ContainerBackend captures the docker interactions of the simulation API.
@@ -91,4 +93,26 @@ type Builder interface {
 // ClientMetadata is metadata to describe the client in more detail, configured with a YAML file in the client dir.
 type ClientMetadata struct {
 	Roles []string `yaml:"roles" json:"roles"`
+
+	// Ports maps port names (e.g. "rpc", "ws", "engine", "beacon-api", "p2p",
+	// "metrics") to the TCP ports opened by the client.
+	Ports map[string]uint16 `yaml:"ports,omitempty" json:"ports,omitempty"`
+
+	// Forks lists the forks supported by the client.
+	Forks []string `yaml:"forks,omitempty" json:"forks,omitempty"`
+
+	// Features lists optional features implemented by the client.
+	Features []string `yaml:"features,omitempty" json:"features,omitempty"`
+}
+
+// checkLivePort returns the port which is checked to determine whether the client has
+// started. This is the RPC port, or the beacon API port for clients without RPC.
+func (m *ClientMetadata) checkLivePort() uint16 {
+	if port, ok := m.Ports[simapi.PortRPC]; ok {
+		return port
+	}
+	if port, ok := m.Ports[simapi.PortBeaconAPI]; ok {
+		return port
+	}
+	return 8545
 }
@@ -0,0 +1,97 @@
+package libhive
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// Event types published by TestManager.
+const (
+	EventSimStarted    = "simStarted"
+	EventSimEnded      = "simEnded"
+	EventSuiteStarted  = "suiteStarted"
+	EventSuiteEnded    = "suiteEnded"
+	EventTestStarted   = "testStarted"
+	EventTestEnded     = "testEnded"
+	EventClientStarted = "clientStarted"
+)
+
+// EventsDir is the directory containing event logs, relative to the log directory.
+const EventsDir = "live"
+
+// Event is a progress event of a simulation run. Events are written to the event log
+// while the simulation is running, allowing tools like hiveview to display the
+// progress of a run before the suite result files are written.
+type Event struct {
+	Type   string      `json:"type"`
+	Time   time.Time   `json:"time"`
+	Suite  TestSuiteID `json:"suite"`
+	Test   TestID      `json:"test,omitempty"`
+	Name   string      `json:"name,omitempty"`   // simulator, suite or test name
+	SimLog string      `json:"simLog,omitempty"` // simulator log file (for simStarted)
+	Client *ClientInfo `json:"client,omitempty"` // started client (for clientStarted)
+	Result *TestResult `json:"result,omitempty"` // test result, without details (for testEnded)
+}
+
+// EventLog writes events as JSON lines.
+type EventLog struct {
+	mu  sync.Mutex
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+// NewEventLog creates an event log writing to w.
+func NewEventLog(w io.WriteCloser) *EventLog {
+	return &EventLog{w: w, enc: json.NewEncoder(w)}
+}
+
+// CreateEventLog creates an event log file in the events directory of logdir.
+// The name parameter is a path relative to logdir.
+func CreateEventLog(logdir, name string) (*EventLog, error) {
+	file := filepath.Join(logdir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewEventLog(f), nil
+}
+
+// Publish writes an event to the log. If the event time is not set,
+// the current time is used.
+func (l *EventLog) Publish(ev Event) {
+	if l == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.enc == nil {
+		return
+	}
+	if err := l.enc.Encode(&ev); err != nil {
+		log15.Warn("can't write event log", "err", err)
+	}
+}
+
+// Close closes the underlying writer. Events published after
+// closing the log are discarded.
+func (l *EventLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enc = nil
+	return l.w.Close()
+}
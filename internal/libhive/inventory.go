@@ -176,6 +176,11 @@ func loadClientMetadata(path string) (m ClientMetadata, err error) {
 	if err := dec.Decode(&m); err != nil {
 		return m, fmt.Errorf("error in %s: %v", path, err)
 	}
+	for name, port := range m.Ports {
+		if name == "" || port == 0 {
+			return m, fmt.Errorf("error in %s: invalid port %q: %d", path, name, port)
+		}
+	}
 	return m, nil
 }
 
@@ -0,0 +1,156 @@
+package libhive
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// This is the label value used for tests which did not start any clients.
+const noClientLabel = "none"
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	testsStartedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "hive",
+		Name:      "tests_started_total",
+		Help:      "Number of started test cases, per client involved in the test.",
+	}, []string{"suite", "client"})
+
+	testsPassedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "hive",
+		Name:      "tests_passed_total",
+		Help:      "Number of passed test cases, per client involved in the test.",
+	}, []string{"suite", "client"})
+
+	testsFailedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "hive",
+		Name:      "tests_failed_total",
+		Help:      "Number of failed test cases, per client involved in the test.",
+	}, []string{"suite", "client"})
+
+	testsSkippedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "hive",
+		Name:      "tests_skipped_total",
+		Help:      "Number of skipped test cases, per client involved in the test.",
+	}, []string{"suite", "client"})
+
+	clientStartHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "hive",
+		Name:      "client_start_seconds",
+		Help:      "Time taken by client containers to start, including the liveness check.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"client", "result"})
+
+	clientContainersGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "hive",
+		Name:      "client_containers",
+		Help:      "Number of client containers currently running.",
+	})
+
+	apiRequestHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "hive",
+		Name:      "api_request_seconds",
+		Help:      "Latency of simulation API requests.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"method", "route"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		testsStartedCounter,
+		testsPassedCounter,
+		testsFailedCounter,
+		testsSkippedCounter,
+		clientStartHistogram,
+		clientContainersGauge,
+		apiRequestHistogram,
+	)
+}
+
+// MetricsHandler returns an HTTP handler which serves hive metrics
+// in the Prometheus/OpenMetrics text format.
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// recordTestClient counts a test as started for a client, when the test starts
+// its first client of that name. It must be called before the client is added to
+// the test's ClientInfo.
+func recordTestClient(suite string, test *TestCase, client string) {
+	for _, c := range test.ClientInfo {
+		if c.Name == client {
+			return
+		}
+	}
+	testsStartedCounter.WithLabelValues(suite, client).Inc()
+}
+
+// recordTestResult updates the test result counters. Tests which did not start
+// any clients are also counted as started here, since their client label is only
+// known when they end.
+func recordTestResult(suite string, test *TestCase) {
+	var counter *prometheus.CounterVec
+	switch {
+	case test.SummaryResult.Skipped:
+		counter = testsSkippedCounter
+	case test.SummaryResult.Pass:
+		counter = testsPassedCounter
+	default:
+		counter = testsFailedCounter
+	}
+	if len(test.ClientInfo) == 0 {
+		testsStartedCounter.WithLabelValues(suite, noClientLabel).Inc()
+	}
+	for _, client := range testClientNames(test) {
+		counter.WithLabelValues(suite, client).Inc()
+	}
+}
+
+// testClientNames returns the names of all clients involved in a test.
+func testClientNames(test *TestCase) []string {
+	set := make(map[string]struct{})
+	for _, c := range test.ClientInfo {
+		set[c.Name] = struct{}{}
+	}
+	if len(set) == 0 {
+		return []string{noClientLabel}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordClientStart updates the client start latency histogram.
+func recordClientStart(client string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	clientStartHistogram.WithLabelValues(client, result).Observe(time.Since(start).Seconds())
+}
+
+// apiMetricsMiddleware measures the latency of simulation API requests.
+// Requests are grouped by the path template of the matched route.
+func apiMetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+
+		route := "unknown"
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tmpl, err := cr.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+		apiRequestHistogram.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
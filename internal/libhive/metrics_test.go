@@ -0,0 +1,57 @@
+package libhive
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTestsStartedCounter(t *testing.T) {
+	var (
+		suite   = "metrics-test"
+		started = func(client string) float64 {
+			return testutil.ToFloat64(testsStartedCounter.WithLabelValues(suite, client))
+		}
+	)
+
+	// A test with two clients of the same type and one of another type.
+	test := &TestCase{ClientInfo: make(map[string]*ClientInfo)}
+	for i, name := range []string{"geth", "geth", "besu"} {
+		recordTestClient(suite, test, name)
+		test.ClientInfo[string(rune('a'+i))] = &ClientInfo{Name: name}
+	}
+	recordTestResult(suite, test)
+
+	// A test without clients.
+	recordTestResult(suite, &TestCase{})
+
+	if v := started("geth"); v != 1 {
+		t.Errorf("wrong started count for geth: %v", v)
+	}
+	if v := started("besu"); v != 1 {
+		t.Errorf("wrong started count for besu: %v", v)
+	}
+	if v := started(noClientLabel); v != 1 {
+		t.Errorf("wrong started count for tests without clients: %v", v)
+	}
+}
+
+// This test checks that failed tests are never recorded as skipped.
+func TestEndTestFailedNotSkipped(t *testing.T) {
+	tm := NewTestManager(SimEnv{}, nil, nil)
+	suiteID, err := tm.StartTestSuite("skip-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testID, err := tm.StartTest(suiteID, "failed", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.EndTest(suiteID, testID, &TestResult{Pass: false, Skipped: true}); err != nil {
+		t.Fatal(err)
+	}
+	tc := tm.runningTestSuites[suiteID].TestCases[testID]
+	if tc.SummaryResult.Skipped {
+		t.Error("failed test is marked as skipped")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -105,7 +106,18 @@ func (r *Runner) Run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 		return SimResult{}, err
 	}
 	writeInstanceInfo(env.LogDir)
-	return r.run(ctx, sim, env)
+	suites, err := r.run(ctx, sim, env, false)
+	return countResults(suites), err
+}
+
+// ListTests runs the simulator in list mode. In this mode, the simulator reports its
+// suites and tests without running them, and no result files are written. The returned
+// suites contain the tests which would run with the test pattern of env.
+func (r *Runner) ListTests(ctx context.Context, sim string, env SimEnv) (map[TestSuiteID]*TestSuite, error) {
+	if err := createWorkspace(env.LogDir); err != nil {
+		return nil, err
+	}
+	return r.run(ctx, sim, env, true)
 }
 
 // RunDevMode starts simulator development mode. In this mode, the simulator is not
@@ -158,9 +170,13 @@ HIVE_SIMULATOR=http://%v
 	return nil
 }
 
-// run runs one simulation.
-func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, error) {
-	log15.Info(fmt.Sprintf("running simulation: %s", sim))
+// run runs one simulation and returns the results of all suites.
+func (r *Runner) run(ctx context.Context, sim string, env SimEnv, listTests bool) (map[TestSuiteID]*TestSuite, error) {
+	if listTests {
+		log15.Info(fmt.Sprintf("listing tests of simulation: %s", sim))
+	} else {
+		log15.Info(fmt.Sprintf("running simulation: %s", sim))
+	}
 
 	clientDefs := make([]*ClientDefinition, 0)
 	if env.ClientList == nil {
@@ -177,24 +193,31 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 				}
 			}
 			if !found {
-				return SimResult{}, fmt.Errorf("unknown client %q in simulation client list", client.Client)
+				return nil, fmt.Errorf("unknown client %q in simulation client list", client.Client)
 			}
 		}
 	}
 
-	// Start the simulation API.
-	tm := NewTestManager(env, r.container, clientDefs)
+	// Start the simulation API. In list mode, the test manager
+	// doesn't write any files because tests are not really run.
+	tmEnv := env
+	if listTests {
+		tmEnv.LogDir = ""
+	}
+	tm := NewTestManager(tmEnv, r.container, clientDefs)
 	defer func() {
 		if err := tm.Terminate(); err != nil {
 			log15.Error("could not terminate test manager", "error", err)
 		}
+		tm.publish(Event{Type: EventSimEnded, Name: sim})
+		tm.events.Close()
 	}()
 
 	log15.Debug("starting simulator API server")
 	server, err := r.container.ServeAPI(ctx, tm.API())
 	if err != nil {
 		log15.Error("can't start API server", "err", err)
-		return SimResult{}, err
+		return nil, err
 	}
 	defer shutdownServer(server)
 
@@ -208,20 +231,38 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 			"HIVE_RANDOM_SEED":  strconv.Itoa(env.SimRandomSeed),
 		},
 	}
+	if listTests {
+		opts.Env["HIVE_LIST_TESTS"] = "true"
+	}
 	containerID, err := r.container.CreateContainer(ctx, r.simImages[sim], opts)
 	if err != nil {
-		return SimResult{}, err
+		return nil, err
 	}
 
-	// Set the log file, and notify TestManager about the container.
+	// Set the log file, and notify TestManager about the container. In list mode,
+	// the simulator output is discarded like all other results.
 	logbasename := fmt.Sprintf("%d-simulator-%s.log", time.Now().Unix(), containerID)
-	opts.LogFile = filepath.Join(env.LogDir, logbasename)
+	if !listTests {
+		opts.LogFile = filepath.Join(env.LogDir, logbasename)
+	}
 	tm.SetSimContainerInfo(containerID, logbasename)
 
+	// Create the event log, which allows following the progress of the run.
+	if !listTests {
+		eventLogName := fmt.Sprintf("%s/%d-%s.jsonl", EventsDir, time.Now().Unix(), containerID)
+		events, err := CreateEventLog(env.LogDir, eventLogName)
+		if err != nil {
+			log15.Warn("can't create event log", "err", err)
+		} else {
+			tm.SetEventLog(events)
+			events.Publish(Event{Type: EventSimStarted, Name: sim, SimLog: logbasename})
+		}
+	}
+
 	log15.Debug("starting simulator container")
 	sc, err := r.container.StartContainer(ctx, containerID, opts)
 	if err != nil {
-		return SimResult{}, err
+		return nil, err
 	}
 	slogger := log15.New("sim", sim, "container", sc.ID[:8])
 	slogger.Debug("started simulator container")
@@ -256,15 +297,27 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 		err = errSimInterrupt
 	}
 
-	// Count the results.
-	var result SimResult
-	for _, suite := range tm.Results() {
+	return tm.Results(), err
+}
+
+// countResults summarizes the results of a simulation run.
+func countResults(suites map[TestSuiteID]*TestSuite) SimResult {
+	result := SimResult{
+		FailedTests:  make(map[string][]string),
+		SuiteClients: make(map[string][]string),
+	}
+	for _, suite := range suites {
 		var suiteFailCounted bool
 		result.Suites++
+		result.SuiteClients[suite.Name] = suite.ClientNames()
 		for _, test := range suite.TestCases {
 			result.Tests++
+			if test.SummaryResult.Skipped {
+				result.TestsSkipped++
+			}
 			if !test.SummaryResult.Pass {
 				result.TestsFailed++
+				result.FailedTests[suite.Name] = append(result.FailedTests[suite.Name], test.Name)
 				if !suiteFailCounted {
 					result.SuitesFailed++
 					suiteFailCounted = true
@@ -272,8 +325,10 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 			}
 		}
 	}
-
-	return result, err
+	for _, names := range result.FailedTests {
+		sort.Strings(names)
+	}
+	return result
 }
 
 // shutdownServer gracefully terminates the HTTP server.
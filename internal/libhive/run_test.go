@@ -1,7 +1,9 @@
 package libhive_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -12,6 +14,7 @@ import (
 	"github.com/ethereum/hive/hivesim"
 	"github.com/ethereum/hive/internal/fakes"
 	"github.com/ethereum/hive/internal/libhive"
+	"github.com/ethereum/hive/internal/simapi"
 )
 
 func TestRunner(t *testing.T) {
@@ -59,6 +62,117 @@ func TestRunner(t *testing.T) {
 	t.Logf("hive.json content: %s", content)
 }
 
+func TestRunnerEvents(t *testing.T) {
+	inv := makeTestInventory()
+	b := fakes.NewBuilder(&fakes.BuilderHooks{})
+	cb := fakes.NewContainerBackend(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			if strings.Contains(image, "/simulator/") {
+				sim := hivesim.NewAt(opt.Env["HIVE_SIMULATOR"])
+				suite, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+				if err != nil {
+					t.Fatal("StartSuite failed:", err)
+				}
+				test, err := sim.StartTest(suite, &simapi.TestRequest{Name: "test"})
+				if err != nil {
+					t.Fatal("StartTest failed:", err)
+				}
+				sim.EndTest(suite, test, hivesim.TestResult{Pass: true})
+				sim.EndSuite(suite)
+			}
+			return new(libhive.ContainerInfo), nil
+		},
+	})
+
+	var (
+		runner = libhive.NewRunner(inv, b, cb)
+		simOpt = libhive.SimEnv{LogDir: t.TempDir()}
+		ctx    = context.Background()
+	)
+	if err := runner.Build(ctx, []libhive.ClientDesignator{{Client: "client-1"}}, []string{"sim-1"}); err != nil {
+		t.Fatal("Build() failed:", err)
+	}
+	if _, err := runner.Run(ctx, "sim-1", simOpt); err != nil {
+		t.Fatal("Run() failed:", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(simOpt.LogDir, libhive.EventsDir, "*.jsonl"))
+	if len(files) != 1 {
+		t.Fatalf("expected one event log, found %d", len(files))
+	}
+	content, _ := os.ReadFile(files[0])
+	var types []string
+	dec := json.NewDecoder(bytes.NewReader(content))
+	for dec.More() {
+		var ev libhive.Event
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatal("invalid event:", err)
+		}
+		types = append(types, ev.Type)
+	}
+	want := []string{
+		libhive.EventSimStarted,
+		libhive.EventSuiteStarted,
+		libhive.EventTestStarted,
+		libhive.EventTestEnded,
+		libhive.EventSuiteEnded,
+		libhive.EventSimEnded,
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("wrong events %v, want %v", types, want)
+	}
+}
+
+func TestRunnerListTests(t *testing.T) {
+	inv := makeTestInventory()
+	b := fakes.NewBuilder(&fakes.BuilderHooks{})
+	cb := fakes.NewContainerBackend(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			if strings.Contains(image, "/simulator/") {
+				if opt.Env["HIVE_LIST_TESTS"] != "true" {
+					t.Error("HIVE_LIST_TESTS not set for simulator")
+				}
+				if opt.LogFile != "" {
+					t.Errorf("simulator log file %q set in list mode", opt.LogFile)
+				}
+				sim := hivesim.NewAt(opt.Env["HIVE_SIMULATOR"])
+				suite, _ := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+				test, _ := sim.StartTest(suite, &simapi.TestRequest{Name: "test"})
+				sim.StartClientWithOptions(suite, test, "client-1")
+				sim.EndTest(suite, test, hivesim.TestResult{Pass: true})
+				sim.EndSuite(suite)
+			} else if opt.LogFile != "" {
+				t.Errorf("client log file %q set in list mode", opt.LogFile)
+			}
+			return new(libhive.ContainerInfo), nil
+		},
+	})
+
+	var (
+		runner = libhive.NewRunner(inv, b, cb)
+		simOpt = libhive.SimEnv{LogDir: t.TempDir()}
+		ctx    = context.Background()
+	)
+	if err := runner.Build(ctx, []libhive.ClientDesignator{{Client: "client-1"}}, []string{"sim-1"}); err != nil {
+		t.Fatal("Build() failed:", err)
+	}
+	suites, err := runner.ListTests(ctx, "sim-1", simOpt)
+	if err != nil {
+		t.Fatal("ListTests() failed:", err)
+	}
+	if len(suites) != 1 || len(suites[0].TestCases) != 1 || suites[0].TestCases[1].Name != "test" {
+		t.Fatalf("wrong suites: %+v", suites)
+	}
+
+	// No results should be written.
+	for _, pattern := range []string{"*.json", libhive.EventsDir + "/*.jsonl"} {
+		files, _ := filepath.Glob(filepath.Join(simOpt.LogDir, pattern))
+		if len(files) != 0 {
+			t.Errorf("list mode wrote files: %v", files)
+		}
+	}
+}
+
 func makeTestInventory() libhive.Inventory {
 	var inv libhive.Inventory
 	inv.AddClient("client-1", nil)
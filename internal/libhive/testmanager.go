@@ -46,16 +46,28 @@ type SimEnv struct {
 	ClientList []ClientDesignator
 
 	// This configures the amount of time the simulation waits
-	// for the client to open port 8545 after launching the container.
+	// for the client to open its RPC port after launching the container.
 	ClientStartTimeout time.Duration
+
+	// This is the maximum total size of artifacts attached to a test.
+	// If unset, DefaultArtifactSizeLimit is used.
+	ArtifactSizeLimit int64
 }
 
 // SimResult summarizes the results of a simulation run.
 type SimResult struct {
-	Suites       int
-	SuitesFailed int
-	Tests        int
-	TestsFailed  int
+	Suites       int `json:"suites"`
+	SuitesFailed int `json:"suitesFailed"`
+	Tests        int `json:"tests"`
+	TestsFailed  int `json:"testsFailed"`
+	TestsSkipped int `json:"testsSkipped"`
+
+	// FailedTests contains the names of failed tests, keyed by suite name.
+	FailedTests map[string][]string `json:"failedTests"`
+
+	// SuiteClients contains the sorted names of the clients started by each suite,
+	// keyed by suite name.
+	SuiteClients map[string][]string `json:"suiteClients"`
 }
 
 // TestManager collects test results during a simulation run.
@@ -66,6 +78,7 @@ type TestManager struct {
 
 	simContainerID string
 	simLogFile     string
+	events         *EventLog
 
 	// all networks started by a specific test suite, where key
 	// is network name and value is network ID
@@ -100,6 +113,16 @@ func (manager *TestManager) SetSimContainerInfo(id, logFile string) {
 	manager.simLogFile = logFile
 }
 
+// SetEventLog sets the log which receives progress events.
+func (manager *TestManager) SetEventLog(events *EventLog) {
+	manager.events = events
+}
+
+// publish writes an event to the event log, if there is one.
+func (manager *TestManager) publish(ev Event) {
+	manager.events.Publish(ev)
+}
+
 // Results returns the results for all suites that have already ended.
 func (manager *TestManager) Results() map[TestSuiteID]*TestSuite {
 	manager.testSuiteMutex.RLock()
@@ -366,6 +389,7 @@ func (manager *TestManager) doEndSuite(testSuite TestSuiteID) error {
 	// Move the suite to results.
 	delete(manager.runningTestSuites, testSuite)
 	manager.results[testSuite] = suite
+	manager.publish(Event{Type: EventSuiteEnded, Suite: testSuite, Name: suite.Name})
 	return nil
 }
 
@@ -405,11 +429,13 @@ func (manager *TestManager) StartTestSuite(name string, description string) (Tes
 		testDetailsFile: testLogFile,
 	}
 	manager.testSuiteCounter++
+	manager.publish(Event{Type: EventSuiteStarted, Suite: newSuiteID, Name: name})
 	return newSuiteID, nil
 }
 
-// StartTest starts a new test case, returning the testcase id as a context identifier
-func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, description string) (TestID, error) {
+// StartTest starts a new test case, returning the testcase id as a context identifier.
+// The parent is the ID of the test which started this test, or zero for top-level tests.
+func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, description string, parent TestID) (TestID, error) {
 	manager.testCaseMutex.Lock()
 	defer manager.testCaseMutex.Unlock()
 
@@ -418,6 +444,10 @@ func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, desc
 	if !ok {
 		return 0, ErrNoSuchTestSuite
 	}
+	// check if the parent test exists in the suite
+	if _, ok := testSuite.TestCases[parent]; parent != 0 && !ok {
+		return 0, ErrNoSuchTestCase
+	}
 	// increment the testcasecounter
 	manager.testCaseCounter++
 	var newCaseID = TestID(manager.testCaseCounter)
@@ -425,6 +455,7 @@ func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, desc
 	newTestCase := &TestCase{
 		Name:        name,
 		Description: description,
+		Parent:      parent,
 		Start:       time.Now(),
 	}
 	// add the test case to the test suite
@@ -432,6 +463,7 @@ func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, desc
 	// and to the general map of id:testcases
 	manager.runningTestCases[newCaseID] = newTestCase
 
+	manager.publish(Event{Type: EventTestStarted, Suite: testSuiteID, Test: newCaseID, Name: name, Time: newTestCase.Start})
 	return newCaseID, nil
 }
 
@@ -454,6 +486,11 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 		return ErrNoSummaryResult
 	}
 
+	// A failed test is never reported as skipped.
+	if !result.Pass {
+		result.Skipped = false
+	}
+
 	// Add the results to the test case
 	testCase.End = time.Now()
 	if result.Details != "" && testSuite.testDetailsFile != nil {
@@ -462,6 +499,7 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 		result.LogOffsets = offsets
 	}
 	testCase.SummaryResult = *result
+	recordTestResult(testSuite.Name, testCase)
 
 	// Stop running clients.
 	for _, v := range testCase.ClientInfo {
@@ -469,11 +507,20 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 			manager.backend.DeleteContainer(v.ID)
 			v.wait()
 			v.wait = nil
+			clientContainersGauge.Dec()
 		}
 	}
 
 	// Delete from running, if it's still there.
 	delete(manager.runningTestCases, testID)
+	manager.publish(Event{
+		Type:   EventTestEnded,
+		Suite:  suiteID,
+		Test:   testID,
+		Name:   testCase.Name,
+		Time:   testCase.End,
+		Result: &TestResult{Pass: result.Pass, Timeout: result.Timeout, Skipped: result.Skipped},
+	})
 	return nil
 }
 
@@ -514,10 +561,25 @@ func (manager *TestManager) RegisterNode(testID TestID, nodeID string, nodeInfo
 	if testCase.ClientInfo == nil {
 		testCase.ClientInfo = make(map[string]*ClientInfo)
 	}
+	recordTestClient(manager.testSuiteName(testID), testCase, nodeInfo.Name)
 	testCase.ClientInfo[nodeID] = nodeInfo
+	if nodeInfo.wait != nil {
+		clientContainersGauge.Inc()
+	}
 	return nil
 }
 
+// testSuiteName returns the name of the suite containing a running test.
+// It must be called with testCaseMutex held.
+func (manager *TestManager) testSuiteName(testID TestID) string {
+	for _, suite := range manager.runningTestSuites {
+		if _, ok := suite.TestCases[testID]; ok {
+			return suite.Name
+		}
+	}
+	return ""
+}
+
 // StopNode stops a client container.
 func (manager *TestManager) StopNode(testID TestID, nodeID string) error {
 	manager.testCaseMutex.Lock()
@@ -538,6 +600,7 @@ func (manager *TestManager) StopNode(testID TestID, nodeID string) error {
 		}
 		nodeInfo.wait()
 		nodeInfo.wait = nil
+		clientContainersGauge.Dec()
 	}
 	return nil
 }
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// webhook posts the report as JSON to a URL.
+type webhook struct {
+	url     string
+	headers map[string]string
+}
+
+func newWebhook(cfg NotifierConfig) (*webhook, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook url is required")
+	}
+	headers := make(map[string]string, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	return &webhook{url: cfg.URL, headers: headers}, nil
+}
+
+func (n *webhook) Send(ctx context.Context, r *Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// email sends a summary of the report via SMTP.
+type email struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func newEmail(cfg NotifierConfig) (*email, error) {
+	if cfg.SMTP == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email notifier requires smtp, from and to")
+	}
+	host, _, err := net.SplitHostPort(cfg.SMTP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address: %v", err)
+	}
+	n := &email{addr: cfg.SMTP, from: cfg.From, to: cfg.To}
+	if cfg.Username != "" {
+		n.auth = smtp.PlainAuth("", cfg.Username, os.ExpandEnv(cfg.Password), host)
+	}
+	return n, nil
+}
+
+func (n *email) Send(ctx context.Context, r *Report) error {
+	subject := fmt.Sprintf("hive: %s: %d/%d tests failed", r.Simulator, r.Result.TestsFailed, r.Result.Tests)
+	if len(r.NewFailures) > 0 {
+		subject += " (new failures)"
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(r.summary(), "\n", "\r\n"))
+
+	// smtp.SendMail does not support contexts, so run it in the
+	// background and give up waiting when the context is done.
+	errc := make(chan error, 1)
+	go func() { errc <- smtp.SendMail(n.addr, n.auth, n.from, n.to, msg.Bytes()) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// command runs an executable with the JSON report on stdin.
+type command struct {
+	args []string
+}
+
+func newCommand(cfg NotifierConfig) (*command, error) {
+	if len(cfg.Command) == 0 {
+		return nil, errors.New("command is required")
+	}
+	return &command{args: cfg.Command}, nil
+}
+
+func (n *command) Send(ctx context.Context, r *Report) error {
+	input, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, n.args[0], n.args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
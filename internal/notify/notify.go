@@ -0,0 +1,328 @@
+// Package notify implements notifications about the results of simulation runs.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+	"gopkg.in/yaml.v3"
+)
+
+// Notification filters.
+const (
+	WhenAlways      = "always"       // notify after every run
+	WhenFailures    = "failures"     // notify when any test failed
+	WhenNewFailures = "new-failures" // notify when tests failed which passed in the previous run
+)
+
+// sendTimeout is the time limit for sending a single notification.
+const sendTimeout = time.Minute
+
+// Config is the notification configuration file.
+type Config struct {
+	Notifiers []NotifierConfig `yaml:"notifiers"`
+}
+
+// NotifierConfig configures a single notifier.
+type NotifierConfig struct {
+	Type      string `yaml:"type"`      // "webhook", "email" or "command"
+	When      string `yaml:"when"`      // notification filter, defaults to "failures"
+	Simulator string `yaml:"simulator"` // regular expression matching simulator names
+
+	// webhook
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+
+	// email
+	SMTP     string   `yaml:"smtp"` // server address, host:port
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+
+	// command
+	Command []string `yaml:"command"`
+}
+
+// Notifier delivers a run report.
+type Notifier interface {
+	Send(ctx context.Context, r *Report) error
+}
+
+type notifierEntry struct {
+	name     string
+	when     string
+	simRegex *regexp.Regexp
+	n        Notifier
+}
+
+// Dispatcher sends reports to the configured notifiers.
+type Dispatcher struct {
+	notifiers []notifierEntry
+}
+
+// LoadConfig reads a notification configuration file.
+func LoadConfig(file string) (*Dispatcher, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseConfig(f)
+}
+
+// ParseConfig parses a YAML notification configuration.
+func ParseConfig(r io.Reader) (*Dispatcher, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return NewDispatcher(cfg.Notifiers)
+}
+
+// NewDispatcher creates notifiers from the given configurations.
+func NewDispatcher(configs []NotifierConfig) (*Dispatcher, error) {
+	d := new(Dispatcher)
+	for i, cfg := range configs {
+		e, err := newNotifierEntry(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %d: %v", i, err)
+		}
+		d.notifiers = append(d.notifiers, e)
+	}
+	return d, nil
+}
+
+func newNotifierEntry(cfg NotifierConfig) (e notifierEntry, err error) {
+	e.name = cfg.Type
+	switch cfg.When {
+	case "":
+		e.when = WhenFailures
+	case WhenAlways, WhenFailures, WhenNewFailures:
+		e.when = cfg.When
+	default:
+		return e, fmt.Errorf("invalid filter %q", cfg.When)
+	}
+	if cfg.Simulator != "" {
+		if e.simRegex, err = regexp.Compile(cfg.Simulator); err != nil {
+			return e, fmt.Errorf("invalid simulator pattern: %v", err)
+		}
+	}
+	switch cfg.Type {
+	case "webhook":
+		e.n, err = newWebhook(cfg)
+	case "email":
+		e.n, err = newEmail(cfg)
+	case "command":
+		e.n, err = newCommand(cfg)
+	case "":
+		err = errors.New("missing notifier type")
+	default:
+		err = fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+	return e, err
+}
+
+// Send delivers the report to all notifiers whose filter matches it.
+func (d *Dispatcher) Send(ctx context.Context, r *Report) error {
+	var errs []string
+	for _, e := range d.notifiers {
+		if !e.matches(r) {
+			continue
+		}
+		sctx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := e.n.Send(sctx, r)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", e.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (e *notifierEntry) matches(r *Report) bool {
+	if e.simRegex != nil && !e.simRegex.MatchString(r.Simulator) {
+		return false
+	}
+	switch e.when {
+	case WhenFailures:
+		return r.Result.TestsFailed > 0
+	case WhenNewFailures:
+		return len(r.NewFailures) > 0
+	default:
+		return true
+	}
+}
+
+// Report is the notification payload.
+type Report struct {
+	Simulator string            `json:"simulator"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Result    libhive.SimResult `json:"result"`
+
+	// NewFailures contains the tests which failed in this run, but not in the
+	// previous run of the same suite with the same clients, keyed by suite name.
+	NewFailures map[string][]string `json:"newFailures"`
+}
+
+// NewReport creates the report of a simulation run which started at the given time.
+// Results of previous runs are read from logdir to determine new failures.
+func NewReport(logdir, sim string, start time.Time, result libhive.SimResult) (*Report, error) {
+	r := &Report{
+		Simulator:   sim,
+		Start:       start,
+		End:         time.Now(),
+		Result:      result,
+		NewFailures: make(map[string][]string),
+	}
+	if len(result.FailedTests) == 0 {
+		return r, nil
+	}
+	suites := make(map[string][]string, len(result.FailedTests))
+	for name := range result.FailedTests {
+		suites[name] = result.SuiteClients[name]
+	}
+	previous, err := previousFailures(logdir, suites, start)
+	if err != nil {
+		return r, err
+	}
+	for suite, failed := range result.FailedTests {
+		prev, ok := previous[suite]
+		for _, name := range failed {
+			if !ok || !prev[name] {
+				r.NewFailures[suite] = append(r.NewFailures[suite], name)
+			}
+		}
+	}
+	return r, nil
+}
+
+// previousFailures finds the most recent result files of the given suites which were
+// written before the start time, and returns their failed tests. The suites map
+// contains the client names of each suite, and a result file is only used when it
+// was run with the same clients. Suites without a previous result file are not
+// contained in the returned map.
+func previousFailures(logdir string, suites map[string][]string, start time.Time) (map[string]map[string]bool, error) {
+	entries, err := os.ReadDir(logdir)
+	if err != nil {
+		return nil, err
+	}
+	// Result files are named <unix time>-<random>.json, so sorting
+	// by name in reverse order visits the most recent files first.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() > entries[j].Name()
+	})
+
+	result := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if len(result) == len(suites) {
+			break
+		}
+		t, ok := resultFileTime(entry)
+		if !ok || t >= start.Unix() {
+			continue
+		}
+		suite, err := readSuite(filepath.Join(logdir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		clients, ok := suites[suite.Name]
+		if !ok || !sameClients(clients, suite.ClientNames()) {
+			continue
+		}
+		if _, seen := result[suite.Name]; seen {
+			continue
+		}
+		failed := make(map[string]bool)
+		for _, test := range suite.TestCases {
+			if !test.SummaryResult.Pass {
+				failed[test.Name] = true
+			}
+		}
+		result[suite.Name] = failed
+	}
+	return result, nil
+}
+
+// sameClients reports whether two sorted client name lists are equal.
+func sameClients(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// resultFileTime returns the timestamp in the name of a suite result file.
+func resultFileTime(entry os.DirEntry) (int64, bool) {
+	name := entry.Name()
+	if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+		return 0, false
+	}
+	prefix, _, found := strings.Cut(name, "-")
+	if !found {
+		return 0, false
+	}
+	t, err := strconv.ParseInt(prefix, 10, 64)
+	return t, err == nil
+}
+
+func readSuite(file string) (*libhive.TestSuite, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var suite libhive.TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+// summary returns a human-readable summary of the report.
+func (r *Report) summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Simulation %s finished at %s.\n", r.Simulator, r.End.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "%d suites, %d tests, %d failed, %d skipped.\n", r.Result.Suites, r.Result.Tests, r.Result.TestsFailed, r.Result.TestsSkipped)
+	writeTestList(&b, "New failures", r.NewFailures)
+	writeTestList(&b, "All failures", r.Result.FailedTests)
+	return b.String()
+}
+
+func writeTestList(w io.Writer, title string, tests map[string][]string) {
+	if len(tests) == 0 {
+		return
+	}
+	suites := make([]string, 0, len(tests))
+	for name := range tests {
+		suites = append(suites, name)
+	}
+	sort.Strings(suites)
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, suite := range suites {
+		for _, test := range tests[suite] {
+			fmt.Fprintf(w, "  %s: %s\n", suite, test)
+		}
+	}
+}
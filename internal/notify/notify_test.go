@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+// writeSuite writes a suite result file with the given clients and test results to dir.
+func writeSuite(t *testing.T, dir string, timestamp int64, name string, clients []string, results map[string]bool) {
+	t.Helper()
+	suite := libhive.TestSuite{
+		Name:           name,
+		ClientVersions: make(map[string]string),
+		TestCases:      make(map[libhive.TestID]*libhive.TestCase),
+	}
+	for _, client := range clients {
+		suite.ClientVersions[client] = "v1"
+	}
+	id := libhive.TestID(1)
+	for test, pass := range results {
+		suite.TestCases[id] = &libhive.TestCase{Name: test, SummaryResult: libhive.TestResult{Pass: pass}}
+		id++
+	}
+	data, err := json.Marshal(&suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, fmt.Sprintf("%d-%x-%s.json", timestamp, name, strings.Join(clients, "-")))
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewFailures(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1000, 0)
+	geth := []string{"go-ethereum"}
+	writeSuite(t, dir, 800, "s1", geth, map[string]bool{"a": false, "b": false, "c": true})
+	writeSuite(t, dir, 900, "s1", geth, map[string]bool{"a": false, "b": true, "c": true})
+	writeSuite(t, dir, 900, "s2", geth, map[string]bool{"x": false})
+	// This run used different clients and must not be used as the previous run.
+	writeSuite(t, dir, 950, "s1", []string{"besu"}, map[string]bool{"a": false, "b": false, "c": false})
+	// This is the result of the current run and must not be used as the previous run.
+	writeSuite(t, dir, 1001, "s1", geth, map[string]bool{"a": false, "b": false, "c": false})
+
+	result := libhive.SimResult{
+		Suites:      3,
+		Tests:       6,
+		TestsFailed: 5,
+		FailedTests: map[string][]string{
+			"s1": {"a", "b", "c"},
+			"s2": {"x"},
+			"s3": {"y"},
+		},
+		SuiteClients: map[string][]string{
+			"s1": geth,
+			"s2": geth,
+			"s3": geth,
+		},
+	}
+	r, err := NewReport(dir, "sim", start, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"s1": {"b", "c"},
+		"s3": {"y"},
+	}
+	if !reflect.DeepEqual(r.NewFailures, want) {
+		t.Fatalf("wrong new failures %v, want %v", r.NewFailures, want)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		received []*Report
+		header   string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report Report
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		header = r.Header.Get("Authorization")
+		received = append(received, &report)
+	}))
+	defer srv.Close()
+
+	config := fmt.Sprintf(`
+notifiers:
+  - type: webhook
+    when: new-failures
+    url: %s
+    headers:
+      Authorization: Bearer secret
+`, srv.URL)
+	d, err := ParseConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Failures, but no new failures: the filter should prevent the notification.
+	report := &Report{
+		Simulator:   "sim",
+		Result:      libhive.SimResult{Tests: 2, TestsFailed: 1, FailedTests: map[string][]string{"s1": {"a"}}},
+		NewFailures: map[string][]string{},
+	}
+	if err := d.Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("webhook called without new failures")
+	}
+
+	report.NewFailures = map[string][]string{"s1": {"a"}}
+	if err := d.Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("webhook called %d times, want 1", len(received))
+	}
+	if header != "Bearer secret" {
+		t.Errorf("wrong Authorization header %q", header)
+	}
+	if !reflect.DeepEqual(received[0], report) {
+		t.Errorf("wrong payload %+v, want %+v", received[0], report)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "report.json")
+	d, err := NewDispatcher([]NotifierConfig{
+		{Type: "command", When: WhenAlways, Command: []string{"sh", "-c", "cat > " + out}},
+		{Type: "command", Simulator: "^other$", Command: []string{"false"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &Report{Simulator: "sim", Result: libhive.SimResult{Tests: 1}}
+	if err := d.Send(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Simulator != "sim" || got.Result.Tests != 1 {
+		t.Fatalf("wrong report written by command: %s", data)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []string{
+		"notifiers: [{type: webhook}]",
+		"notifiers: [{type: pager, url: x}]",
+		"notifiers: [{type: webhook, url: x, when: sometimes}]",
+		"notifiers: [{type: email, smtp: localhost:25, from: a@b}]",
+		"notifiers: [{type: command, command: [x], unknown: 1}]",
+	}
+	for _, cfg := range tests {
+		if _, err := ParseConfig(strings.NewReader(cfg)); err == nil {
+			t.Errorf("no error for config %q", cfg)
+		}
+	}
+}
@@ -1,12 +1,23 @@
 // Package simapi contains definitions of JSON objects used in the simulation API.
 package simapi
 
+// Well-known client port names, used in the ports section of client metadata.
+const (
+	PortRPC       = "rpc"        // JSON-RPC over HTTP
+	PortWS        = "ws"         // JSON-RPC over WebSocket
+	PortEngine    = "engine"     // engine API
+	PortBeaconAPI = "beacon-api" // beacon node HTTP API
+	PortP2P       = "p2p"        // peer-to-peer networking
+	PortMetrics   = "metrics"    // metrics endpoint
+)
+
 type TestRequest struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 	Location    string `json:"location"`
 	Category    string `json:"category"`
 	Description string `json:"description"`
+	Parent      uint32 `json:"parent,omitempty"` // ID of the parent test, zero for top-level tests
 }
 
 // NodeConfig contains the launch parameters for a client container.
@@ -10,8 +10,10 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	config_prague "github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
 )
 
 var (
@@ -41,7 +43,7 @@ type Genesis interface {
 	Nonce() uint64
 	SetNonce(nonce uint64)
 	Timestamp() uint64
-	SetTimestamp(timestamp int64, cancun bool)
+	SetTimestamp(timestamp int64, cancun, prague bool)
 	ExtraData() []byte
 	SetExtraData(data []byte)
 	GasLimit() uint64
@@ -176,6 +178,14 @@ type NethermindParams struct {
 	Eip1153TransitionTimestamp              string `json:"eip1153TransitionTimestamp,omitempty"`
 	Eip5656TransitionTimestamp              string `json:"eip5656TransitionTimestamp,omitempty"`
 	Eip6780TransitionTimestamp              string `json:"eip6780TransitionTimestamp,omitempty"`
+	Eip2537TransitionTimestamp              string `json:"eip2537TransitionTimestamp,omitempty"`
+	Eip2935TransitionTimestamp              string `json:"eip2935TransitionTimestamp,omitempty"`
+	Eip6110TransitionTimestamp              string `json:"eip6110TransitionTimestamp,omitempty"`
+	Eip7002TransitionTimestamp              string `json:"eip7002TransitionTimestamp,omitempty"`
+	Eip7251TransitionTimestamp              string `json:"eip7251TransitionTimestamp,omitempty"`
+	Eip7702TransitionTimestamp              string `json:"eip7702TransitionTimestamp,omitempty"`
+	Eip7623TransitionTimestamp              string `json:"eip7623TransitionTimestamp,omitempty"`
+	DepositContractAddress                  string `json:"depositContractAddress,omitempty"`
 	Eip4844BlobGasPriceUpdateFraction       string `json:"eip4844BlobGasPriceUpdateFraction"`
 	Eip4844MaxBlobGasPerBlock               string `json:"eip4844MaxBlobGasPerBlock"`
 	Eip4844MinBlobGasPrice                  string `json:"eip4844MinBlobGasPrice"`
@@ -261,6 +271,16 @@ func (n *NethermindChainSpec) Config() *params.ChainConfig {
 		return nil
 	}
 
+	var pragueTime *uint64
+	if n.Params.Eip7002TransitionTimestamp != "" {
+		t, err := strconv.ParseUint(n.Params.Eip7002TransitionTimestamp[2:], 16, 64)
+		if err != nil {
+			fmt.Println("Error parsing hexadecimal timestamp:", err)
+			return nil
+		}
+		pragueTime = &t
+	}
+
 	return &params.ChainConfig{
 		ChainID:                       chainID,
 		HomesteadBlock:                big.NewInt(0),
@@ -281,6 +301,7 @@ func (n *NethermindChainSpec) Config() *params.ChainConfig {
 		MergeNetsplitBlock:            big.NewInt(0),
 		ShanghaiTime:                  &unixTimestampUint64,
 		CancunTime:                    &unixTimestampUint64,
+		PragueTime:                    pragueTime,
 		TerminalTotalDifficulty:       big.NewInt(ttd),
 		TerminalTotalDifficultyPassed: false,
 		Ethash:                        &params.EthashConfig{},
@@ -308,7 +329,7 @@ func (n *NethermindChainSpec) Timestamp() uint64 {
 	panic("implement me")
 }
 
-func (n *NethermindChainSpec) SetTimestamp(timestamp int64, cancun bool) {
+func (n *NethermindChainSpec) SetTimestamp(timestamp int64, cancun, prague bool) {
 	//n.Params.TerminalTotalDifficulty = fmt.Sprintf("%v", timestamp)
 	n.Params.Eip3651TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
 	n.Params.Eip4895TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
@@ -322,6 +343,23 @@ func (n *NethermindChainSpec) SetTimestamp(timestamp int64, cancun bool) {
 		n.Params.Eip5656TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
 		n.Params.Eip6780TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
 	}
+	if prague {
+		n.Params.Eip2537TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip2935TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip6110TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip7002TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip7251TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip7702TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.Eip7623TransitionTimestamp = fmt.Sprintf("%#x", timestamp)
+		n.Params.DepositContractAddress = config_prague.DEPOSIT_CONTRACT_ADDRESS.Hex()
+		for address, code := range config_prague.SystemContracts() {
+			n.Accounts[address.Hex()] = Account{
+				"balance": "0x0",
+				"nonce":   "0x1",
+				"code":    hexutil.Encode(code),
+			}
+		}
+	}
 }
 
 func (n *NethermindChainSpec) ExtraData() []byte {
@@ -473,6 +511,8 @@ type ErigonConfig struct {
 	TerminalTotalDifficultyPassed bool       `json:"terminalTotalDifficultyPassed"`
 	ShanghaiTimestamp             *big.Int   `json:"shanghaiTime"`
 	CancunTime                    *big.Int   `json:"cancunTime"`
+	PragueTime                    *big.Int   `json:"pragueTime,omitempty"`
+	DepositContractAddress        string     `json:"depositContractAddress,omitempty"`
 	MinBlobGasPrice               int        `json:"minBlobGasPrice"`
 	MaxBlobGasPerBlock            int        `json:"maxBlobGasPerBlock"`
 	TargetBlobGasPerBlock         int        `json:"targetBlobGasPerBlock"`
@@ -482,6 +522,7 @@ type ErigonConfig struct {
 
 type ErigonAccount struct {
 	Balance     string `json:"balance"`
+	Nonce       string `json:"nonce,omitempty"`
 	Constructor string `json:"constructor,omitempty"`
 	Code        string `json:"code"`
 }
@@ -515,12 +556,17 @@ func (v *ErigonGenesis) Config() *params.ChainConfig {
 	ttd := big.NewInt(0).SetBytes(common.Hex2Bytes(v.ErigonDifficulty))
 	shangai := v.ErigonConfig.ShanghaiTimestamp.Uint64() //big.NewInt(v.ErigonConfig.ShanghaiTimestamp
 	cancun := v.ErigonConfig.CancunTime.Uint64()         //big.NewInt(v.ErigonConfig.ShanghaiTimestamp
-	return &params.ChainConfig{
+	config := &params.ChainConfig{
 		ChainID:                 chainID,
 		TerminalTotalDifficulty: ttd,
 		ShanghaiTime:            &shangai,
 		CancunTime:              &cancun,
 	}
+	if v.ErigonConfig.PragueTime != nil {
+		prague := v.ErigonConfig.PragueTime.Uint64()
+		config.PragueTime = &prague
+	}
+	return config
 }
 
 func (v *ErigonGenesis) SetConfig(config *params.ChainConfig) {
@@ -543,13 +589,27 @@ func (v *ErigonGenesis) Timestamp() uint64 {
 	panic("implement me")
 }
 
-func (v *ErigonGenesis) SetTimestamp(timestamp int64, cancun bool) {
+func (v *ErigonGenesis) SetTimestamp(timestamp int64, cancun, prague bool) {
 	v.ErigonConfig.ShanghaiTimestamp = big.NewInt(timestamp)
 	if cancun {
 		v.ErigonConfig.CancunTime = big.NewInt(timestamp)
 	} else {
 		v.ErigonConfig.CancunTime = big.NewInt(timestamp+12000000)
 	}
+	if prague {
+		v.ErigonConfig.PragueTime = big.NewInt(timestamp)
+		v.ErigonConfig.DepositContractAddress = config_prague.DEPOSIT_CONTRACT_ADDRESS.Hex()
+		for address, code := range config_prague.SystemContracts() {
+			v.ErigonAlloc[address.Hex()] = ErigonAccount{
+				Balance: "0x0",
+				Nonce:   "0x1",
+				Code:    hexutil.Encode(code),
+			}
+		}
+	} else {
+		v.ErigonConfig.PragueTime = nil
+		v.ErigonConfig.DepositContractAddress = ""
+	}
 }
 
 func (v *ErigonGenesis) ExtraData() []byte {
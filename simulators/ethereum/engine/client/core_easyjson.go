@@ -103,6 +103,20 @@ func easyjson3d34c335DecodeGithubComEthereumHiveSimulatorsEthereumEngineHelper(i
 			out.Eip5656TransitionTimestamp = string(in.String())
 		case "eip6780TransitionTimestamp":
 			out.Eip6780TransitionTimestamp = string(in.String())
+		case "eip2537TransitionTimestamp":
+			out.Eip2537TransitionTimestamp = string(in.String())
+		case "eip2935TransitionTimestamp":
+			out.Eip2935TransitionTimestamp = string(in.String())
+		case "eip6110TransitionTimestamp":
+			out.Eip6110TransitionTimestamp = string(in.String())
+		case "eip7002TransitionTimestamp":
+			out.Eip7002TransitionTimestamp = string(in.String())
+		case "eip7251TransitionTimestamp":
+			out.Eip7251TransitionTimestamp = string(in.String())
+		case "eip7702TransitionTimestamp":
+			out.Eip7702TransitionTimestamp = string(in.String())
+		case "eip7623TransitionTimestamp":
+			out.Eip7623TransitionTimestamp = string(in.String())
 		case "eip4844BlobGasPriceUpdateFraction":
 			out.Eip4844BlobGasPriceUpdateFraction = string(in.String())
 		case "eip4844MaxBlobGasPerBlock":
@@ -467,6 +481,76 @@ func easyjson3d34c335EncodeGithubComEthereumHiveSimulatorsEthereumEngineHelper(o
 		}
 		out.String(string(in.Eip6780TransitionTimestamp))
 	}
+	if in.Eip2537TransitionTimestamp != "" {
+		const prefix string = ",\"eip2537TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip2537TransitionTimestamp))
+	}
+	if in.Eip2935TransitionTimestamp != "" {
+		const prefix string = ",\"eip2935TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip2935TransitionTimestamp))
+	}
+	if in.Eip6110TransitionTimestamp != "" {
+		const prefix string = ",\"eip6110TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip6110TransitionTimestamp))
+	}
+	if in.Eip7002TransitionTimestamp != "" {
+		const prefix string = ",\"eip7002TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip7002TransitionTimestamp))
+	}
+	if in.Eip7251TransitionTimestamp != "" {
+		const prefix string = ",\"eip7251TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip7251TransitionTimestamp))
+	}
+	if in.Eip7702TransitionTimestamp != "" {
+		const prefix string = ",\"eip7702TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip7702TransitionTimestamp))
+	}
+	if in.Eip7623TransitionTimestamp != "" {
+		const prefix string = ",\"eip7623TransitionTimestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Eip7623TransitionTimestamp))
+	}
 	{
 		const prefix string = ",\"eip4844BlobGasPriceUpdateFraction\":"
 		if first {
@@ -1291,6 +1375,18 @@ func easyjson3d34c335DecodeGithubComEthereumHiveSimulatorsEthereumEngineHelper5(
 					in.AddError((*out.CancunTime).UnmarshalJSON(data))
 				}
 			}
+		case "pragueTime":
+			if in.IsNull() {
+				in.Skip()
+				out.PragueTime = nil
+			} else {
+				if out.PragueTime == nil {
+					out.PragueTime = new(big.Int)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.PragueTime).UnmarshalJSON(data))
+				}
+			}
 		case "minBlobGasPrice":
 			out.MinBlobGasPrice = int(in.Int())
 		case "maxBlobGasPerBlock":
@@ -1417,6 +1513,11 @@ func easyjson3d34c335EncodeGithubComEthereumHiveSimulatorsEthereumEngineHelper5(
 			out.Raw((*in.CancunTime).MarshalJSON())
 		}
 	}
+	if in.PragueTime != nil {
+		const prefix string = ",\"pragueTime\":"
+		out.RawString(prefix)
+		out.Raw((*in.PragueTime).MarshalJSON())
+	}
 	{
 		const prefix string = ",\"minBlobGasPrice\":"
 		out.RawString(prefix)
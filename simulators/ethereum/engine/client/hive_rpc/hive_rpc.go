@@ -98,7 +98,7 @@ func (s HiveRPCEngineStarter) StartClient(T *hivesim.T, testContext context.Cont
 	}
 
 	// Start the client and create the engine client object
-	clientName := strings.Split(cs[0].Name, "_")[0]
+	clientName := strings.Split(clientType, "_")[0]
 	genesisStart, err := helper.GenesisStartOptionBasedOnClient(genesis, clientName)
 	if err != nil {
 		return nil, err
@@ -478,6 +478,9 @@ func (ec *HiveRPCEngineClient) GetPayload(ctx context.Context, version int, payl
 		blockValue = response.BlockValue
 		blobsBundle = response.BlobsBundle
 		shouldOverrideBuilder = response.ShouldOverrideBuilder
+		if version >= 4 && response.ExecutionRequests != nil {
+			executableData.ExecutionRequests = &response.ExecutionRequests
+		}
 	} else {
 		err = ec.c.CallContext(ctx, &executableData, rpcString, payloadId)
 	}
@@ -499,6 +502,10 @@ func (ec *HiveRPCEngineClient) GetPayloadV3(ctx context.Context, payloadId *api.
 	return ec.GetPayload(ctx, 3, payloadId)
 }
 
+func (ec *HiveRPCEngineClient) GetPayloadV4(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	return ec.GetPayload(ctx, 4, payloadId)
+}
+
 // Get Payload Bodies API Calls
 func (ec *HiveRPCEngineClient) GetPayloadBodiesByRangeV1(ctx context.Context, start uint64, count uint64) ([]*typ.ExecutionPayloadBodyV1, error) {
 	var (
@@ -546,7 +553,16 @@ func (ec *HiveRPCEngineClient) NewPayload(ctx context.Context, version int, payl
 		return result, err
 	}
 
-	if version >= 3 {
+	if version >= 4 {
+		var requests []hexutil.Bytes
+		if payload.ExecutionRequests != nil {
+			requests = make([]hexutil.Bytes, len(*payload.ExecutionRequests))
+			for i, r := range *payload.ExecutionRequests {
+				requests[i] = r
+			}
+		}
+		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload, payload.VersionedHashes, payload.ParentBeaconBlockRoot, requests)
+	} else if version >= 3 {
 		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload, payload.VersionedHashes, payload.ParentBeaconBlockRoot)
 	} else {
 		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload)
@@ -570,6 +586,11 @@ func (ec *HiveRPCEngineClient) NewPayloadV3(ctx context.Context, payload *typ.Ex
 	return ec.NewPayload(ctx, 3, payload)
 }
 
+func (ec *HiveRPCEngineClient) NewPayloadV4(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error) {
+	ec.latestPayloadSent = payload
+	return ec.NewPayload(ctx, 4, payload)
+}
+
 // Exchange Transition Configuration API Call Methods
 func (ec *HiveRPCEngineClient) ExchangeTransitionConfigurationV1(ctx context.Context, tConf *api.TransitionConfigurationV1) (api.TransitionConfigurationV1, error) {
 	var result api.TransitionConfigurationV1
@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -60,6 +61,10 @@ type GethNodeEngineStarter struct {
 
 var (
 	DefaultMaxPeers = big.NewInt(25)
+
+	// ErrPragueNotSupported is returned for V4 engine API calls and when starting
+	// the node with a genesis that has Prague enabled.
+	ErrPragueNotSupported = errors.New("prague is not supported by the in-process geth node")
 )
 
 func (s GethNodeEngineStarter) StartClient(T *hivesim.T, testContext context.Context, genesis *core.Genesis, ClientParams hivesim.Params, ClientFiles hivesim.Params, bootClients ...client.EngineClient) (client.EngineClient, error) {
@@ -72,6 +77,12 @@ func (s GethNodeEngineStarter) StartGethNode(T *hivesim.T, testContext context.C
 		err error
 	)
 
+	// The engine API of the in-process node ends at V3, so it can't follow a chain
+	// which activates Prague.
+	if genesis.Config.PragueTime != nil {
+		return nil, ErrPragueNotSupported
+	}
+
 	if ttd == nil {
 		if ttdStr, ok := ClientParams["HIVE_TERMINAL_TOTAL_DIFFICULTY"]; ok {
 			// Retrieve TTD from parameters
@@ -440,6 +451,8 @@ func (n *GethNode) NewPayload(ctx context.Context, version int, pl *typ.Executab
 		return n.NewPayloadV2(ctx, pl)
 	case 3:
 		return n.NewPayloadV3(ctx, pl)
+	case 4:
+		return n.NewPayloadV4(ctx, pl)
 	}
 	return beacon.PayloadStatusV1{}, fmt.Errorf("unknown version %d", version)
 }
@@ -480,6 +493,10 @@ func (n *GethNode) NewPayloadV3(ctx context.Context, pl *typ.ExecutableData) (be
 	return resp, err
 }
 
+func (n *GethNode) NewPayloadV4(ctx context.Context, pl *typ.ExecutableData) (beacon.PayloadStatusV1, error) {
+	return beacon.PayloadStatusV1{}, ErrPragueNotSupported
+}
+
 func (n *GethNode) ForkchoiceUpdated(ctx context.Context, version int, fcs *beacon.ForkchoiceStateV1, payload *typ.PayloadAttributes) (beacon.ForkChoiceResponse, error) {
 	switch version {
 	case 1:
@@ -560,6 +577,10 @@ func (n *GethNode) GetPayloadV3(ctx context.Context, payloadId *beacon.PayloadID
 	return ed, p.BlockValue, blobsBundle, &p.Override, err
 }
 
+func (n *GethNode) GetPayloadV4(ctx context.Context, payloadId *beacon.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	return typ.ExecutableData{}, nil, nil, nil, ErrPragueNotSupported
+}
+
 func (n *GethNode) GetPayload(ctx context.Context, version int, payloadId *beacon.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
 
 	switch version {
@@ -571,6 +592,8 @@ func (n *GethNode) GetPayload(ctx context.Context, version int, payloadId *beaco
 		return ed, value, nil, nil, err
 	case 3:
 		return n.GetPayloadV3(ctx, payloadId)
+	case 4:
+		return n.GetPayloadV4(ctx, payloadId)
 	default:
 		return typ.ExecutableData{}, nil, nil, nil, fmt.Errorf("unknown version %d", version)
 	}
@@ -525,6 +525,12 @@ func (cl *CLMocker) GetNextPayload() {
 		}
 		cl.LatestPayloadBuilt.ParentBeaconBlockRoot = cl.LatestPayloadAttributes.BeaconRoot
 	}
+	if cl.IsPrague(cl.LatestPayloadBuilt.Timestamp) {
+		// The execution requests are sent back to all clients along with the payload
+		if cl.LatestPayloadBuilt.ExecutionRequests == nil {
+			cl.Fatalf("CLMocker: No execution requests on prague")
+		}
+	}
 	cl.LatestPayloadBuilt.PayloadAttributes = cl.LatestPayloadAttributes
 }
 
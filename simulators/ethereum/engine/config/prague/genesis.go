@@ -0,0 +1,31 @@
+package prague
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// ConfigGenesis configures the genesis block for the Prague fork.
+func ConfigGenesis(genesis *core.Genesis, forkTimestamp uint64) error {
+	if genesis.Config.CancunTime == nil {
+		return fmt.Errorf("prague fork requires cancun fork")
+	}
+	if *genesis.Config.CancunTime > forkTimestamp {
+		return fmt.Errorf("prague fork must be after cancun fork")
+	}
+	genesis.Config.PragueTime = &forkTimestamp
+
+	// Add the system contracts of EIP-2935, EIP-7002 and EIP-7251. Note the deposit
+	// contract address of EIP-6110 can't be set here, because the chain config has
+	// no field for it.
+	for address, code := range SystemContracts() {
+		genesis.Alloc[address] = core.GenesisAccount{
+			Balance: common.Big0,
+			Nonce:   1,
+			Code:    code,
+		}
+	}
+	return nil
+}
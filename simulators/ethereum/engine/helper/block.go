@@ -1,9 +1,13 @@
 package helper
 
 import (
+	"crypto/sha256"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 func GenerateInvalidPayloadBlock(baseBlock *types.Block, uncle *types.Block, payloadField InvalidPayloadBlockField) (*types.Block, error) {
@@ -24,3 +28,40 @@ func GenerateInvalidPayloadBlock(baseBlock *types.Block, uncle *types.Block, pay
 
 	return baseBlock, nil
 }
+
+// CalcRequestsHash computes the EIP-7685 commitment to the execution requests of a block.
+// Requests without data are skipped.
+func CalcRequestsHash(requests [][]byte) common.Hash {
+	h := sha256.New()
+	for _, r := range requests {
+		if len(r) > 1 {
+			rh := sha256.Sum256(r)
+			h.Write(rh[:])
+		}
+	}
+	return common.BytesToHash(h.Sum(nil))
+}
+
+// CalcHeaderHashWithRequests computes the hash of a Prague header. The requests hash is
+// the last field of the header, which the go-ethereum header type does not have yet, so
+// it is appended to the RLP encoding of the header.
+func CalcHeaderHashWithRequests(header *types.Header, requestsHash common.Hash) (common.Hash, error) {
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(enc, &fields); err != nil {
+		return common.Hash{}, err
+	}
+	rh, err := rlp.EncodeToBytes(requestsHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	fields = append(fields, rh)
+	enc, err = rlp.EncodeToBytes(fields)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(enc), nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
 )
@@ -317,6 +318,8 @@ type CustomPayloadData struct {
 	ParentBeaconRoot          *common.Hash
 	RemoveParentBeaconRoot    bool
 	VersionedHashesCustomizer VersionedHashesCustomizer
+	ExecutionRequests         *[][]byte
+	RemoveExecutionRequests   bool
 }
 
 var _ PayloadCustomizer = (*CustomPayloadData)(nil)
@@ -427,6 +430,22 @@ func (customData *CustomPayloadData) CustomizePayload(randSource *rand.Rand, bas
 		customPayloadHeader.ParentBeaconRoot = basePayload.ParentBeaconBlockRoot
 	}
 
+	var executionRequests *[][]byte
+	if customData.RemoveExecutionRequests {
+		executionRequests = nil
+	} else if customData.ExecutionRequests != nil {
+		executionRequests = customData.ExecutionRequests
+	} else if basePayload.ExecutionRequests != nil {
+		executionRequests = basePayload.ExecutionRequests
+	}
+	blockHash := customPayloadHeader.Hash()
+	if executionRequests != nil {
+		blockHash, err = CalcHeaderHashWithRequests(&customPayloadHeader, CalcRequestsHash(*executionRequests))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Return the new payload
 	result := &typ.ExecutableData{
 		ParentHash:    customPayloadHeader.ParentHash,
@@ -441,13 +460,14 @@ func (customData *CustomPayloadData) CustomizePayload(randSource *rand.Rand, bas
 		Timestamp:     customPayloadHeader.Time,
 		ExtraData:     customPayloadHeader.Extra,
 		BaseFeePerGas: customPayloadHeader.BaseFee,
-		BlockHash:     customPayloadHeader.Hash(),
+		BlockHash:     blockHash,
 		Transactions:  txs,
 		BlobGasUsed:   customPayloadHeader.BlobGasUsed,
 		ExcessBlobGas: customPayloadHeader.ExcessBlobGas,
 
 		// Metadata
 		ParentBeaconBlockRoot: customPayloadHeader.ParentBeaconRoot,
+		ExecutionRequests:     executionRequests,
 		PayloadAttributes:     basePayload.PayloadAttributes,
 	}
 
@@ -585,6 +605,9 @@ func (customData *CustomPayloadData) String() string {
 	if customData.Withdrawals != nil {
 		customFieldsList = append(customFieldsList, fmt.Sprintf("Withdrawals=%v", customData.Withdrawals))
 	}
+	if customData.ExecutionRequests != nil {
+		customFieldsList = append(customFieldsList, fmt.Sprintf("ExecutionRequests=%x", *customData.ExecutionRequests))
+	}
 	return strings.Join(customFieldsList, ", ")
 }
 
@@ -701,6 +724,18 @@ func GenerateInvalidPayload(randSource *rand.Rand, basePayload *typ.ExecutableDa
 		customPayloadMod = &CustomPayloadData{
 			VersionedHashesCustomizer: &ExtraVersionedHash{},
 		}
+	case InvalidExecutionRequests:
+		if basePayload.ExecutionRequests == nil {
+			return nil, fmt.Errorf("no execution requests available for modification")
+		}
+		// Append a withdrawal request which was not created by the payload transactions
+		request := make([]byte, 1+76)
+		request[0] = prague.WITHDRAWAL_REQUEST_TYPE
+		randSource.Read(request[1:])
+		modExecutionRequests := append(append([][]byte{}, *basePayload.ExecutionRequests...), request)
+		customPayloadMod = &CustomPayloadData{
+			ExecutionRequests: &modExecutionRequests,
+		}
 	case InvalidWithdrawals:
 		// These options are not supported yet.
 		// TODO: Implement
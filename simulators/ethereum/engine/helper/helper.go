@@ -104,6 +104,7 @@ const (
 	InvalidVersionedHashesVersion = "VersionedHashes Version"
 	IncompleteVersionedHashes     = "Incomplete VersionedHashes"
 	ExtraVersionedHashes          = "Extra VersionedHashes"
+	InvalidExecutionRequests      = "ExecutionRequests"
 	RemoveTransaction             = "Incomplete Transactions"
 	InvalidTransactionSignature   = "Transaction Signature"
 	InvalidTransactionNonce       = "Transaction Nonce"
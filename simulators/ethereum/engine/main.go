@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
 	"github.com/ethereum/hive/simulators/ethereum/engine/test"
@@ -18,6 +21,7 @@ import (
 	//suite_ex_cap "github.com/ethereum/hive/simulators/ethereum/engine/suites/exchange_capabilities"
 	//suite_transition "github.com/ethereum/hive/simulators/ethereum/engine/suites/transition"
 	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	suite_prague "github.com/ethereum/hive/simulators/ethereum/engine/suites/prague"
 	suite_withdrawals "github.com/ethereum/hive/simulators/ethereum/engine/suites/withdrawals"
 )
 
@@ -64,6 +68,11 @@ func main() {
 			Description: `
 		Test Engine API on Cancun.`[1:],
 		}
+		prague = hivesim.Suite{
+			Name: "engine-prague",
+			Description: `
+		Test Engine API on Prague.`[1:],
+		}
 	)
 	simulator := hivesim.New()
 
@@ -74,6 +83,7 @@ func main() {
 	//suite_sync.AddSyncTestsToSuite(simulator, &sync, suite_sync.Tests)
 	addTestsToSuite(simulator, &withdrawals, suite_withdrawals.Tests, "full")
 	addTestsToSuite(simulator, &cancun, suite_cancun.Tests, "full")
+	addTestsToSuite(simulator, &prague, suite_prague.Tests, "full")
 
 	// Mark suites for execution
 	//hivesim.MustRunSuite(simulator, engine)
@@ -83,6 +93,7 @@ func main() {
 	// hivesim.MustRunSuite(simulator, syncSuite)
 	hivesim.MustRunSuite(simulator, withdrawals)
 	hivesim.MustRunSuite(simulator, cancun)
+	hivesim.MustRunSuite(simulator, prague)
 }
 
 //	func specToInterface(src []test.Spec) []test.SpecInterface {
@@ -110,125 +121,161 @@ func getTimestamp(spec test.Spec) int64 {
 	return shanghaiTimestamp.Unix()
 }
 
-// Add test cases to a given test suite
-func addTestsToSuite(sim *hivesim.Simulation, suite *hivesim.Suite, tests []test.Spec, nodeType string) {
-	for _, currentTest := range tests {
-		currentTest := currentTest
+// clientBaseName returns the name of the client implementation, which selects the
+// genesis format, e.g. "nethermind" for client type "nethermind_gnosis".
+func clientBaseName(clientType string) string {
+	return strings.Split(clientType, "_")[0]
+}
+
+// makeGenesis loads the genesis of a test for the given client type.
+func makeGenesis(spec test.Spec, clientType string, timestamp int64, suiteName string) client.Genesis {
+	genesis := spec.GetGenesis(clientBaseName(clientType))
+	genesis.SetTimestamp(timestamp, suiteName == "engine-cancun" || suiteName == "engine-prague", suiteName == "engine-prague")
+	genesis.SetDifficulty(big.NewInt(100))
+	return genesis
+}
+
+// clientParams returns the client parameters for a test, based on the genesis of the
+// client and the fork configuration of the test.
+func clientParams(genesis client.Genesis, forkConfig *config.ForkConfig, nodeType string) hivesim.Params {
+	// Calculate and set the TTD for this test
+	//ttd := helper.CalculateulateRealTTD(genesis, currentTest.GetTTD())
 
-		// Load the genesis file specified and dynamically bundle it.
-		clientTypes, err := sim.ClientTypes()
-		if err != nil {
-			return
+	// Configure Forks
+	newParams := globals.DefaultClientEnv.Set("HIVE_TERMINAL_TOTAL_DIFFICULTY", fmt.Sprintf("%d", genesis.Difficulty().Uint64()))
+	if forkConfig.LondonNumber != nil {
+		newParams = newParams.Set("HIVE_FORK_LONDON", fmt.Sprintf("%d", forkConfig.LondonNumber))
+	}
+	if forkConfig.ParisNumber != nil {
+		newParams = newParams.Set("HIVE_MERGE_BLOCK_ID", fmt.Sprintf("%d", forkConfig.ParisNumber))
+	}
+	if forkConfig.ShanghaiTimestamp != nil {
+		newParams = newParams.Set("HIVE_SHANGHAI_TIMESTAMP", fmt.Sprintf("%d", forkConfig.ShanghaiTimestamp))
+		// Ensure merge transition is activated before shanghai if not already
+		if forkConfig.ParisNumber == nil {
+			newParams = newParams.Set("HIVE_MERGE_BLOCK_ID", "0")
+		}
+		if forkConfig.CancunTimestamp != nil {
+			newParams = newParams.Set("HIVE_CANCUN_TIMESTAMP", fmt.Sprintf("%d", forkConfig.CancunTimestamp))
 		}
-		if len(clientTypes) != 1 {
-			panic("missing client")
+		if forkConfig.PragueTimestamp != nil {
+			newParams = newParams.Set("HIVE_PRAGUE_TIMESTAMP", fmt.Sprintf("%d", forkConfig.PragueTimestamp))
+		}
+	}
+
+	if nodeType != "" {
+		newParams = newParams.Set("HIVE_NODETYPE", nodeType)
+	}
+
+	//if genesis.Difficulty().Cmp(big.NewInt(ttd)) < 0 {
+	//
+	//	if currentTest.GetChainFile() != "" {
+	//		// We are using a Proof of Work chain file, remove all clique-related settings
+	//		// TODO: Nethermind still requires HIVE_MINER for the Engine API
+	//		// delete(newParams, "HIVE_MINER")
+	//		delete(newParams, "HIVE_CLIQUE_PRIVATEKEY")
+	//		delete(newParams, "HIVE_CLIQUE_PERIOD")
+	//		// Add the new file to be loaded as chain.rlp
+	//		testFiles = testFiles.Set("/chain.rlp", "./chains/"+currentTest.GetChainFile())
+	//	}
+	//	if currentTest.IsMiningDisabled() {
+	//		delete(newParams, "HIVE_MINER")
+	//	}
+	//} else {
+	// This is a post-merge test
+	delete(newParams, "HIVE_CLIQUE_PRIVATEKEY")
+	delete(newParams, "HIVE_CLIQUE_PERIOD")
+	delete(newParams, "HIVE_MINER")
+	newParams = newParams.Set("HIVE_POST_MERGE_GENESIS", "false")
+	newParams = newParams.Set("HIVE_NETWORK_ID", strconv.FormatInt(genesis.Config().ChainID.Int64(), 10))
+	t := strconv.FormatUint(genesis.Difficulty().Uint64(), 10)
+	newParams = newParams.Set("HIVE_TERMINAL_TOTAL_DIFFICULTY", t)
+	//}
+	return newParams
+}
+
+// Add test cases to a given test suite. Every test is added once for each client type
+// of the simulation.
+func addTestsToSuite(sim *hivesim.Simulation, suite *hivesim.Suite, tests []test.Spec, nodeType string) {
+	allClientTypes, err := sim.ClientTypes()
+	if err != nil {
+		panic(fmt.Errorf("unable to get client types: %v", err))
+	}
+	if len(allClientTypes) == 0 {
+		panic("missing client")
+	}
+	// Tests need a genesis in the format of the client, skip clients without one.
+	var clientTypes []*hivesim.ClientDefinition
+	for _, clientType := range allClientTypes {
+		if !test.HasGenesisFormat(clientBaseName(clientType.Name)) {
+			fmt.Fprintf(os.Stderr, "skipping client %s in suite %s: unsupported genesis format\n", clientType.Name, suite.Name)
+			continue
 		}
-		clientName := strings.Split(clientTypes[0].Name, "_")[0]
+		clientTypes = append(clientTypes, clientType)
+	}
+	for _, currentTest := range tests {
+		currentTest := currentTest
 		random_seed := time.Now().Unix()
-		genesis := currentTest.GetGenesis(clientName)
 
-		// Set the timestamp of the genesis to the next 2 minutes
+		// Set the timestamp of the genesis to the next 2 minutes. The genesis of every
+		// client type is created with the same timestamp, so that clients of different
+		// types start from the same genesis block. Tests which launch clients of a
+		// different type check the genesis block hash, and fail if it differs.
 		timestamp := getTimestamp(currentTest)
-		genesis.SetTimestamp(timestamp, suite.Name == "engine-cancun")
-		genesis.SetDifficulty(big.NewInt(100))
-		//genesis.UpdateTimestamp(getTimestamp())
-		genesisStartOption, err := helper.GenesisStartOptionBasedOnClient(genesis, clientName)
-		if err != nil {
-			panic("unable to inject genesis")
+		geneses := make(map[string]client.Genesis, len(clientTypes))
+		for _, clientType := range clientTypes {
+			geneses[clientType.Name] = makeGenesis(currentTest, clientType.Name, timestamp, suite.Name)
 		}
 		forkConfig := currentTest.GetForkConfig()
 		// forkConfig.ConfigGenesis(genesis)
-		// Calculate and set the TTD for this test
-		//ttd := helper.CalculateulateRealTTD(genesis, currentTest.GetTTD())
-
-		// Configure Forks
-		newParams := globals.DefaultClientEnv.Set("HIVE_TERMINAL_TOTAL_DIFFICULTY", fmt.Sprintf("%d", genesis.Difficulty().Uint64()))
-		if forkConfig.LondonNumber != nil {
-			newParams = newParams.Set("HIVE_FORK_LONDON", fmt.Sprintf("%d", forkConfig.LondonNumber))
-		}
-		if forkConfig.ParisNumber != nil {
-			newParams = newParams.Set("HIVE_MERGE_BLOCK_ID", fmt.Sprintf("%d", forkConfig.ParisNumber))
-		}
-		if forkConfig.ShanghaiTimestamp != nil {
-			newParams = newParams.Set("HIVE_SHANGHAI_TIMESTAMP", fmt.Sprintf("%d", forkConfig.ShanghaiTimestamp))
-			// Ensure merge transition is activated before shanghai if not already
-			if forkConfig.ParisNumber == nil {
-				newParams = newParams.Set("HIVE_MERGE_BLOCK_ID", "0")
-			}
-			if forkConfig.CancunTimestamp != nil {
-				newParams = newParams.Set("HIVE_CANCUN_TIMESTAMP", fmt.Sprintf("%d", forkConfig.CancunTimestamp))
-			}
-		}
 
-		if nodeType != "" {
-			newParams = newParams.Set("HIVE_NODETYPE", nodeType)
-		}
+		for _, clientType := range clientTypes {
+			clientType := clientType
 
-		testFiles := hivesim.Params{}
-		//if genesis.Difficulty().Cmp(big.NewInt(ttd)) < 0 {
-		//
-		//	if currentTest.GetChainFile() != "" {
-		//		// We are using a Proof of Work chain file, remove all clique-related settings
-		//		// TODO: Nethermind still requires HIVE_MINER for the Engine API
-		//		// delete(newParams, "HIVE_MINER")
-		//		delete(newParams, "HIVE_CLIQUE_PRIVATEKEY")
-		//		delete(newParams, "HIVE_CLIQUE_PERIOD")
-		//		// Add the new file to be loaded as chain.rlp
-		//		testFiles = testFiles.Set("/chain.rlp", "./chains/"+currentTest.GetChainFile())
-		//	}
-		//	if currentTest.IsMiningDisabled() {
-		//		delete(newParams, "HIVE_MINER")
-		//	}
-		//} else {
-		// This is a post-merge test
-		delete(newParams, "HIVE_CLIQUE_PRIVATEKEY")
-		delete(newParams, "HIVE_CLIQUE_PERIOD")
-		delete(newParams, "HIVE_MINER")
-		newParams = newParams.Set("HIVE_POST_MERGE_GENESIS", "false")
-		newParams = newParams.Set("HIVE_NETWORK_ID", strconv.FormatInt(genesis.Config().ChainID.Int64(), 10))
-		t := strconv.FormatUint(genesis.Difficulty().Uint64(), 10)
-		newParams = newParams.Set("HIVE_TERMINAL_TOTAL_DIFFICULTY", t)
-		//}
-
-		if clientTypes, err := sim.ClientTypes(); err == nil {
-			for _, clientType := range clientTypes {
-				suite.Add(hivesim.TestSpec{
-					Name:        fmt.Sprintf("%s (%s)", currentTest.GetName(), clientType.Name),
-					Description: currentTest.GetAbout(),
-					Run: func(t *hivesim.T) {
-						// Start the client with given options
-						c := t.StartClient(
-							clientType.Name,
-							newParams,
-							genesisStartOption,
-							hivesim.WithStaticFiles(testFiles),
-						)
-						t.Logf("Start test (%s): %s", c.Type, currentTest.GetName())
-						defer func() {
-							t.Logf("End test (%s): %s", c.Type, currentTest.GetName())
-						}()
-						currentTest.GetName()
-						timeout := 30 * time.Minute
-						// If a test.Spec specifies a timeout, use that instead
-						if currentTest.GetTimeout() != 0 {
-							timeout = time.Second * time.Duration(currentTest.GetTimeout())
-						}
-						time.Sleep(30 * time.Second)
-						// Run the test case
-						test.Run(
-							currentTest,
-							genesis.Difficulty(),
-							timeout,
-							t,
-							c,
-							genesis,
-							rand.New(rand.NewSource(random_seed)), newParams,
-							testFiles,
-						)
-					},
-				})
+			// Load the genesis file specified and dynamically bundle it.
+			genesis := geneses[clientType.Name]
+			genesisStartOption, err := helper.GenesisStartOptionBasedOnClient(genesis, clientBaseName(clientType.Name))
+			if err != nil {
+				panic("unable to inject genesis")
 			}
-		}
+			newParams := clientParams(genesis, forkConfig, nodeType)
+			testFiles := hivesim.Params{}
 
+			suite.Add(hivesim.TestSpec{
+				Name:        fmt.Sprintf("%s (%s)", currentTest.GetName(), clientType.Name),
+				Description: currentTest.GetAbout(),
+				Run: func(t *hivesim.T) {
+					// Start the client with given options
+					c := t.StartClient(
+						clientType.Name,
+						newParams,
+						genesisStartOption,
+						hivesim.WithStaticFiles(testFiles),
+					)
+					t.Logf("Start test (%s): %s", c.Type, currentTest.GetName())
+					defer func() {
+						t.Logf("End test (%s): %s", c.Type, currentTest.GetName())
+					}()
+					timeout := 30 * time.Minute
+					// If a test.Spec specifies a timeout, use that instead
+					if currentTest.GetTimeout() != 0 {
+						timeout = time.Second * time.Duration(currentTest.GetTimeout())
+					}
+					time.Sleep(30 * time.Second)
+					// Run the test case
+					test.Run(
+						currentTest,
+						genesis.Difficulty(),
+						timeout,
+						t,
+						c,
+						genesis,
+						geneses,
+						rand.New(rand.NewSource(random_seed)), newParams,
+						testFiles,
+					)
+				},
+			})
+		}
 	}
 }
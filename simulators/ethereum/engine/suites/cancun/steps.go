@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client/hive_rpc"
 	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
@@ -79,6 +80,9 @@ type LaunchClients struct {
 	ClientCount              uint64
 	SkipConnectingToBootnode bool
 	SkipAddingToCLMock       bool
+	// Launch a client of a different type than the client under test, if the
+	// simulation has more than one client type available
+	DifferentClientType bool
 }
 
 func (step LaunchClients) GetClientCount() uint64 {
@@ -90,36 +94,158 @@ func (step LaunchClients) GetClientCount() uint64 {
 }
 
 func (step LaunchClients) Execute(t *CancunTestContext) error {
-	// Launch a new client
-	var (
-		client client.EngineClient
-		err    error
-	)
 	clientCount := step.GetClientCount()
-	for i := uint64(0); i < clientCount; i++ {
-		if !step.SkipConnectingToBootnode {
-			// TODO: Spin up client
-			client, err = step.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles, t.Engines[0])
-		} else {
-			// TODO: Spin up client
-			client, err = step.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+	starter, genesis := step.EngineStarter, t.Genesis
+	if hiveStarter, ok := starter.(hive_rpc.HiveRPCEngineStarter); ok && hiveStarter.ClientType == "" {
+		// Launch the client type under test, unless a different one is requested
+		hiveStarter.ClientType = t.Client.Type
+		if step.DifferentClientType {
+			clientType, err := t.SecondaryClientType()
+			if err != nil {
+				return err
+			}
+			hiveStarter.ClientType = clientType
 		}
+		starter, genesis = hiveStarter, t.ClientGenesis(hiveStarter.ClientType)
+	} else if step.DifferentClientType {
+		return fmt.Errorf("different client type requested, but the engine starter sets the client type")
+	}
+	for i := uint64(0); i < clientCount; i++ {
+		ec, err := step.startClient(t, starter, genesis)
 		if err != nil {
 			return err
 		}
-		t.Engines = append(t.Engines, client)
-		t.TestEngines = append(t.TestEngines, test.NewTestEngineClient(t.Env, client))
+		if step.DifferentClientType {
+			// The genesis files of the client types are maintained separately. Make
+			// sure they describe the same chain, otherwise the clients can't interact.
+			if err := checkSameGenesis(t, t.Engines[0], ec); err != nil {
+				ec.Close()
+				return err
+			}
+		}
+		t.Engines = append(t.Engines, ec)
+		t.TestEngines = append(t.TestEngines, test.NewTestEngineClient(t.Env, ec))
 		if !step.SkipAddingToCLMock {
-			t.CLMock.AddEngineClient(client)
+			t.CLMock.AddEngineClient(ec)
 		}
 	}
 	return nil
 }
 
+func (step LaunchClients) startClient(t *CancunTestContext, starter client.EngineStarter, genesis client.Genesis) (client.EngineClient, error) {
+	if !step.SkipConnectingToBootnode {
+		return starter.StartClient(t.T, t.TestContext, genesis, t.ClientParams, t.ClientFiles, t.Engines[0])
+	}
+	return starter.StartClient(t.T, t.TestContext, genesis, t.ClientParams, t.ClientFiles)
+}
+
+// checkSameGenesis returns an error if two clients have different genesis blocks.
+func checkSameGenesis(t *CancunTestContext, a, b client.EngineClient) error {
+	var hashes [2]common.Hash
+	for i, ec := range []client.EngineClient{a, b} {
+		ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+		header, err := ec.HeaderByNumber(ctx, common.Big0)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("unable to get genesis of client %s: %v", ec.ID(), err)
+		}
+		hashes[i] = header.Hash()
+	}
+	if hashes[0] != hashes[1] {
+		return fmt.Errorf("genesis of client %s differs from client under test: %s != %s", b.ID(), hashes[1], hashes[0])
+	}
+	return nil
+}
+
 func (step LaunchClients) Description() string {
+	if step.DifferentClientType {
+		return fmt.Sprintf("Launch %d new engine client(s) of a different type", step.GetClientCount())
+	}
 	return fmt.Sprintf("Launch %d new engine client(s)", step.GetClientCount())
 }
 
+// A step that waits for a client to sync to the latest head of the CL Mock
+type WaitForSync struct {
+	ClientIndex uint64
+	// Maximum time to wait for the client to sync
+	// Default: 60 seconds
+	TimeoutSeconds uint64
+}
+
+func (step WaitForSync) Execute(t *CancunTestContext) error {
+	if step.ClientIndex >= uint64(len(t.Engines)) {
+		return fmt.Errorf("invalid client index %d", step.ClientIndex)
+	}
+	timeoutSeconds := step.TimeoutSeconds
+	if timeoutSeconds == 0 {
+		timeoutSeconds = 60
+	}
+	var (
+		ec       = t.Engines[step.ClientIndex]
+		expected = t.CLMock.LatestHeader.Hash()
+		timeout  = time.After(time.Duration(timeoutSeconds) * time.Second)
+	)
+	for {
+		ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+		header, err := ec.HeaderByNumber(ctx, nil)
+		cancel()
+		if err == nil && header.Hash() == expected {
+			t.Logf("INFO (%s): Client %d (%s) synced to head %v", t.TestName, step.ClientIndex, ec.ID(), expected)
+			return nil
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-timeout:
+			return fmt.Errorf("client %d (%s) did not sync to head %v in time", step.ClientIndex, ec.ID(), expected)
+		case <-t.TimeoutContext.Done():
+			return fmt.Errorf("test timeout while waiting for client %d (%s) to sync: %v", step.ClientIndex, ec.ID(), t.TimeoutContext.Err())
+		}
+	}
+}
+
+func (step WaitForSync) Description() string {
+	return fmt.Sprintf("WaitForSync: client %d", step.ClientIndex)
+}
+
+// A step that re-orgs a client to a sibling of the latest payload produced by the CL Mock.
+// The client is re-org'd back to the canonical chain on the next payload produced.
+type ReorgLatestPayload struct {
+	ClientIndex uint64
+}
+
+func (step ReorgLatestPayload) Execute(t *CancunTestContext) error {
+	if step.ClientIndex >= uint64(len(t.TestEngines)) {
+		return fmt.Errorf("invalid client index %d", step.ClientIndex)
+	}
+	var (
+		testEngine = t.TestEngines[step.ClientIndex]
+		extraData  = []byte{0x01}
+	)
+	if bytes.Equal(t.CLMock.LatestPayloadBuilt.ExtraData, extraData) {
+		extraData = []byte{0x02}
+	}
+	sidePayload, err := (&helper.CustomPayloadData{
+		ExtraData: &extraData,
+	}).CustomizePayload(t.Rand, &t.CLMock.LatestPayloadBuilt)
+	if err != nil {
+		return errors.Wrap(err, "unable to customize payload")
+	}
+
+	// Send the sibling payload and make it the head of the client
+	r := testEngine.TestEngineNewPayload(sidePayload)
+	r.ExpectStatus(test.Valid)
+	forkchoiceState := t.CLMock.LatestForkchoice
+	forkchoiceState.HeadBlockHash = sidePayload.BlockHash
+	s := testEngine.TestEngineForkchoiceUpdated(&forkchoiceState, nil, sidePayload.Timestamp)
+	s.ExpectPayloadStatus(test.Valid)
+	testEngine.TestHeaderByNumber(nil).ExpectHash(sidePayload.BlockHash)
+	return nil
+}
+
+func (step ReorgLatestPayload) Description() string {
+	return fmt.Sprintf("ReorgLatestPayload: client %d", step.ClientIndex)
+}
+
 // A step that sends a new payload to the client
 type NewPayloads struct {
 	// Payload Count
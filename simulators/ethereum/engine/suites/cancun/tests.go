@@ -564,6 +564,167 @@ var Tests = []test.Spec{
 		},
 	},
 
+	// Mixed client tests
+	&CancunBaseSpec{
+
+		BaseSpec: test.BaseSpec{
+			Name: "Blob Transactions, Mixed Clients Payload Production",
+			About: `
+			Launch a secondary client of a different type than the client
+			under test, and alternate payload production between both
+			clients.
+
+			Verify that the blob transactions are included in payloads built
+			by either client, and that both clients follow the canonical
+			chain.
+
+			The test fails if no other client type of the simulation starts
+			from the same genesis block.
+			`,
+			MainFork: config.Cancun,
+		},
+
+		TestSequence: TestSequence{
+			LaunchClients{
+				EngineStarter:       hive_rpc.HiveRPCEngineStarter{},
+				DifferentClientType: true,
+			},
+
+			// Create a block without any blobs to get past genesis
+			NewPayloads{
+				PayloadCount:              1,
+				ExpectedIncludedBlobCount: 0,
+			},
+
+			SendBlobTransactions{
+				TransactionCount:              cancun.TARGET_BLOBS_PER_BLOCK,
+				BlobsPerTransaction:           1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+				ClientIndex:                   1,
+			},
+			NewPayloads{
+				ExpectedIncludedBlobCount: cancun.TARGET_BLOBS_PER_BLOCK,
+				ExpectedBlobs:             helper.GetBlobList(0, cancun.TARGET_BLOBS_PER_BLOCK),
+			},
+			SendBlobTransactions{
+				TransactionCount:              cancun.TARGET_BLOBS_PER_BLOCK,
+				BlobsPerTransaction:           1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+				ClientIndex:                   0,
+			},
+			NewPayloads{
+				ExpectedIncludedBlobCount: cancun.TARGET_BLOBS_PER_BLOCK,
+				ExpectedBlobs:             helper.GetBlobList(helper.BlobID(cancun.TARGET_BLOBS_PER_BLOCK), cancun.TARGET_BLOBS_PER_BLOCK),
+			},
+			NewPayloads{
+				PayloadCount: 4,
+			},
+			WaitForSync{
+				ClientIndex: 1,
+			},
+		},
+	},
+
+	&CancunBaseSpec{
+
+		BaseSpec: test.BaseSpec{
+			Name: "Blob Transactions, Mixed Clients Sync",
+			About: `
+			Produce a chain with blob transactions on the client under test,
+			then launch a secondary client of a different type, and verify
+			that it syncs the chain from the client under test.
+
+			The test fails if no other client type of the simulation starts
+			from the same genesis block.
+			`,
+			MainFork: config.Cancun,
+		},
+
+		TestSequence: TestSequence{
+			NewPayloads{
+				PayloadCount:              1,
+				ExpectedIncludedBlobCount: 0,
+			},
+			SendBlobTransactions{
+				TransactionCount:              cancun.TARGET_BLOBS_PER_BLOCK,
+				BlobsPerTransaction:           1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			NewPayloads{
+				ExpectedIncludedBlobCount: cancun.TARGET_BLOBS_PER_BLOCK,
+				ExpectedBlobs:             helper.GetBlobList(0, cancun.TARGET_BLOBS_PER_BLOCK),
+			},
+			NewPayloads{
+				PayloadCount: 3,
+			},
+
+			// Launch the secondary client, which starts syncing on the next
+			// forkchoice update sent by the CL Mock
+			LaunchClients{
+				EngineStarter:       hive_rpc.HiveRPCEngineStarter{},
+				DifferentClientType: true,
+			},
+			NewPayloads{},
+			WaitForSync{
+				ClientIndex:    1,
+				TimeoutSeconds: 180,
+			},
+
+			// Both clients must now be able to build on top of the chain
+			NewPayloads{
+				PayloadCount: 2,
+			},
+		},
+	},
+
+	&CancunBaseSpec{
+
+		BaseSpec: test.BaseSpec{
+			Name: "Blob Transactions, Mixed Clients Re-Org",
+			About: `
+			Launch a secondary client of a different type than the client
+			under test, and re-org it to a sibling of the latest payload
+			containing blob transactions.
+
+			Verify that the secondary client re-orgs back to the canonical
+			chain on the next payload produced by the CL Mock.
+
+			The test fails if no other client type of the simulation starts
+			from the same genesis block.
+			`,
+			MainFork: config.Cancun,
+		},
+
+		TestSequence: TestSequence{
+			LaunchClients{
+				EngineStarter:       hive_rpc.HiveRPCEngineStarter{},
+				DifferentClientType: true,
+			},
+			NewPayloads{
+				PayloadCount:              1,
+				ExpectedIncludedBlobCount: 0,
+			},
+			SendBlobTransactions{
+				TransactionCount:              cancun.TARGET_BLOBS_PER_BLOCK,
+				BlobsPerTransaction:           1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			NewPayloads{
+				ExpectedIncludedBlobCount: cancun.TARGET_BLOBS_PER_BLOCK,
+				ExpectedBlobs:             helper.GetBlobList(0, cancun.TARGET_BLOBS_PER_BLOCK),
+			},
+			ReorgLatestPayload{
+				ClientIndex: 1,
+			},
+			NewPayloads{
+				PayloadCount: 2,
+			},
+			WaitForSync{
+				ClientIndex: 1,
+			},
+		},
+	},
+
 	// ForkchoiceUpdatedV3 before cancun
 	// &CancunBaseSpec{
 	// 	BaseSpec: test.BaseSpec{
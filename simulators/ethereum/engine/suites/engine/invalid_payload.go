@@ -66,7 +66,7 @@ func (i InvalidPayloadTestCase) GetName() string {
 func (tc InvalidPayloadTestCase) Execute(t *test.Env) {
 	if tc.Syncing {
 		// To allow sending the primary engine client into SYNCING state, we need a secondary client to guide the payload creation
-		secondaryClient, err := hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+		secondaryClient, err := hive_rpc.HiveRPCEngineStarter{ClientType: t.Client.Type}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
 		if err != nil {
 			t.Fatalf("FAIL (%s): Unable to spawn a secondary client: %v", t.TestName, err)
 		}
@@ -326,7 +326,7 @@ func (i PayloadBuildAfterInvalidPayloadTest) GetName() string {
 
 func (tc PayloadBuildAfterInvalidPayloadTest) Execute(t *test.Env) {
 	// Add a second client to build the invalid payload
-	secondaryEngine, err := hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+	secondaryEngine, err := hive_rpc.HiveRPCEngineStarter{ClientType: t.Client.Type}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
 
 	if err != nil {
 		t.Fatalf("FAIL (%s): Unable to spawn a secondary client: %v", t.TestName, err)
@@ -157,7 +157,7 @@ func (spec InOrderPayloadExecutionTest) Execute(t *test.Env) {
 	r.ExpectBalanceEqual(expectedBalance)
 
 	// Start a second client to send newPayload consecutively without fcU
-	secondaryClient, err := hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+	secondaryClient, err := hive_rpc.HiveRPCEngineStarter{ClientType: t.Client.Type}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
 	if err != nil {
 		t.Fatalf("FAIL (%s): Unable to start secondary client: %v", t.TestName, err)
 	}
@@ -330,7 +330,7 @@ func (spec NewPayloadOnSyncingClientTest) Execute(t *test.Env) {
 	{
 		// To allow sending the primary engine client into SYNCING state, we need a secondary client to guide the payload creation
 		var err error
-		secondaryClient, err = hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+		secondaryClient, err = hive_rpc.HiveRPCEngineStarter{ClientType: t.Client.Type}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
 
 		if err != nil {
 			t.Fatalf("FAIL (%s): Unable to spawn a secondary client: %v", t.TestName, err)
@@ -511,7 +511,7 @@ func (spec NewPayloadWithMissingFcUTest) Execute(t *test.Env) {
 
 	var secondaryEngineTest *test.TestEngineClient
 	{
-		secondaryEngine, err := hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+		secondaryEngine, err := hive_rpc.HiveRPCEngineStarter{ClientType: t.Client.Type}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
 
 		if err != nil {
 			t.Fatalf("FAIL (%s): Unable to spawn a secondary client: %v", t.TestName, err)
@@ -0,0 +1,14 @@
+package suite_prague
+
+import (
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+)
+
+// Contains the base spec for all prague tests.
+//
+// Prague tests are built from the cancun test steps. The CL mocker and the steps pick
+// the engine API version based on the fork config, so the V4 methods are used as soon
+// as Prague is active.
+type PragueBaseSpec struct {
+	suite_cancun.CancunBaseSpec
+}
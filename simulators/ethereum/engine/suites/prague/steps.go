@@ -0,0 +1,40 @@
+package suite_prague
+
+import (
+	"bytes"
+	"fmt"
+
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+)
+
+// A step that verifies the execution requests of the latest payload built by the CL mock.
+type ExecutionRequests struct {
+	// Execution requests expected in the payload, each one prefixed with its request type
+	ExpectedRequests [][]byte
+}
+
+var _ suite_cancun.TestStep = ExecutionRequests{}
+
+func (step ExecutionRequests) Execute(t *suite_cancun.CancunTestContext) error {
+	payload := &t.CLMock.LatestPayloadBuilt
+	if !t.ForkConfig.IsPrague(payload.Timestamp) {
+		return fmt.Errorf("payload %d is not a prague payload", payload.Number)
+	}
+	if payload.ExecutionRequests == nil {
+		return fmt.Errorf("payload %d has no execution requests", payload.Number)
+	}
+	got := *payload.ExecutionRequests
+	if len(got) != len(step.ExpectedRequests) {
+		return fmt.Errorf("unexpected number of execution requests in payload %d: want %d, got %d", payload.Number, len(step.ExpectedRequests), len(got))
+	}
+	for i, want := range step.ExpectedRequests {
+		if !bytes.Equal(got[i], want) {
+			return fmt.Errorf("unexpected execution request %d in payload %d: want %x, got %x", i, payload.Number, want, got[i])
+		}
+	}
+	return nil
+}
+
+func (step ExecutionRequests) Description() string {
+	return fmt.Sprintf("ExecutionRequests: %d requests expected in latest payload", len(step.ExpectedRequests))
+}
@@ -0,0 +1,267 @@
+// # Test suite for prague tests
+package suite_prague
+
+import (
+	"fmt"
+
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	suite_engine "github.com/ethereum/hive/simulators/ethereum/engine/suites/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+var (
+	// A withdrawal request which is not created by any transaction of the payload:
+	// source address (20 bytes), validator pubkey (48 bytes) and amount (8 bytes).
+	unexpectedWithdrawalRequest = append([]byte{prague.WITHDRAWAL_REQUEST_TYPE}, make([]byte, 20+48+8)...)
+)
+
+// Execution specification reference:
+// https://github.com/ethereum/execution-apis/blob/main/src/engine/prague.md
+
+// List of all prague tests
+var Tests = []test.Spec{
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "Execution Requests On Block 1, Cancun Genesis",
+				About: `
+				Tests the Prague fork since Block 1.
+
+				Verifications performed:
+				- Correct implementation of Engine API changes for Prague:
+				  - engine_newPayloadV4, engine_getPayloadV4
+				- Payloads without any requests have an empty list of execution requests
+				`,
+				MainFork: config.Prague,
+				// We fork after genesis
+				ForkHeight: 1,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				// We are starting at Cancun genesis so send a payload to reach the fork
+				suite_cancun.NewPayloads{},
+				suite_cancun.NewPayloads{
+					PayloadCount: 3,
+				},
+				ExecutionRequests{
+					ExpectedRequests: [][]byte{},
+				},
+			},
+		},
+	},
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "Execution Requests On Block 1, Prague Genesis",
+				About: `
+				Tests the Prague fork since genesis.
+
+				Verifications performed:
+				* See Execution Requests On Block 1, Cancun Genesis
+				`,
+				MainFork: config.Prague,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					PayloadCount: 3,
+				},
+				ExecutionRequests{
+					ExpectedRequests: [][]byte{},
+				},
+			},
+		},
+	},
+
+	// GetPayloadV4 Before Prague, Negative Tests
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "GetPayloadV4 To Request Cancun Payload",
+				About: `
+				Test requesting a Cancun PayloadID using GetPayloadV4.
+				Verify that client returns UNSUPPORTED_FORK_ERROR.
+				`,
+				MainFork:   config.Prague,
+				ForkHeight: 2,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					GetPayloadCustomizer: &helper.UpgradeGetPayloadVersion{
+						GetPayloadCustomizer: &helper.BaseGetPayloadCustomizer{
+							ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+						},
+					},
+					ExpectationDescription: fmt.Sprintf(`
+					GetPayloadV4 To Request Cancun Payload must return UNSUPPORTED_FORK_ERROR (code %d)
+					`, *globals.UNSUPPORTED_FORK_ERROR),
+				},
+			},
+		},
+	},
+
+	// GetPayloadV3 After Prague, Negative Tests
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "GetPayloadV3 To Request Prague Payload",
+				About: `
+				Test requesting a Prague PayloadID using GetPayloadV3.
+				Verify that client returns UNSUPPORTED_FORK_ERROR.
+				`,
+				MainFork:   config.Prague,
+				ForkHeight: 1,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					GetPayloadCustomizer: &helper.DowngradeGetPayloadVersion{
+						GetPayloadCustomizer: &helper.BaseGetPayloadCustomizer{
+							ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+						},
+					},
+					ExpectationDescription: fmt.Sprintf(`
+					GetPayloadV3 To Request Prague Payload must return UNSUPPORTED_FORK_ERROR (code %d)
+					`, *globals.UNSUPPORTED_FORK_ERROR),
+				},
+			},
+		},
+	},
+
+	// NewPayloadV4 Before Prague, Negative Tests
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "NewPayloadV4 Before Prague, Empty Execution Requests",
+				About: `
+				Test sending NewPayloadV4 Before Prague with an empty list of execution requests.
+				Verify that client returns UNSUPPORTED_FORK_ERROR.
+				`,
+				MainFork:   config.Prague,
+				ForkHeight: 2,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					NewPayloadCustomizer: &helper.UpgradeNewPayloadVersion{
+						NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+							PayloadCustomizer: &helper.CustomPayloadData{
+								ExecutionRequests: &[][]byte{},
+							},
+							ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+						},
+					},
+					ExpectationDescription: fmt.Sprintf(`
+					NewPayloadV4 before Prague must return UNSUPPORTED_FORK_ERROR (code %d)
+					`, *globals.UNSUPPORTED_FORK_ERROR),
+				},
+			},
+		},
+	},
+
+	// NewPayloadV3 After Prague, Negative Tests
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "NewPayloadV3 After Prague",
+				About: `
+				Test sending a Prague payload using NewPayloadV3.
+				Verify that client returns UNSUPPORTED_FORK_ERROR.
+				`,
+				MainFork:   config.Prague,
+				ForkHeight: 1,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					NewPayloadCustomizer: &helper.DowngradeNewPayloadVersion{
+						NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+							ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+						},
+					},
+					ExpectationDescription: fmt.Sprintf(`
+					NewPayloadV3 after Prague must return UNSUPPORTED_FORK_ERROR (code %d)
+					`, *globals.UNSUPPORTED_FORK_ERROR),
+				},
+			},
+		},
+	},
+
+	// Test execution requests in Engine API NewPayloadV4
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "NewPayloadV4 Execution Requests, Nil Requests",
+				About: `
+				Test sending NewPayloadV4 after Prague with a nil list of execution requests.
+				Verify that client returns INVALID_PARAMS_ERROR.
+				`,
+				MainFork: config.Prague,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+						PayloadCustomizer: &helper.CustomPayloadData{
+							RemoveExecutionRequests: true,
+						},
+						ExpectedError: globals.INVALID_PARAMS_ERROR,
+					},
+					ExpectationDescription: fmt.Sprintf(`
+					NewPayloadV4 with nil execution requests must return INVALID_PARAMS_ERROR (code %d)
+					`, *globals.INVALID_PARAMS_ERROR),
+				},
+			},
+		},
+	},
+	&PragueBaseSpec{
+		CancunBaseSpec: suite_cancun.CancunBaseSpec{
+			BaseSpec: test.BaseSpec{
+				Name: "NewPayloadV4 Execution Requests, Extra Request",
+				About: `
+				Test sending NewPayloadV4 with a withdrawal request which was not created
+				by the transactions of the payload. The block hash is recalculated to commit
+				to the modified requests.
+				Verify that client returns INVALID status.
+				`,
+				MainFork: config.Prague,
+			},
+
+			TestSequence: suite_cancun.TestSequence{
+				suite_cancun.NewPayloads{
+					NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+						PayloadCustomizer: &helper.CustomPayloadData{
+							ExecutionRequests: &[][]byte{unexpectedWithdrawalRequest},
+						},
+						ExpectInvalidStatus: true,
+					},
+					ExpectationDescription: `
+					NewPayloadV4 with incorrect list of execution requests must return INVALID status
+					`,
+				},
+			},
+		},
+	},
+}
+
+func init() {
+	// Invalid Payload Tests
+	for _, invalidField := range []helper.InvalidPayloadBlockField{
+		helper.InvalidExecutionRequests,
+	} {
+		for _, syncing := range []bool{false, true} {
+			Tests = append(Tests, suite_engine.InvalidPayloadTestCase{
+				BaseSpec: test.BaseSpec{
+					MainFork: config.Prague,
+				},
+				InvalidField: invalidField,
+				Syncing:      syncing,
+			})
+		}
+	}
+}
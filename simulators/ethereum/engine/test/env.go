@@ -2,9 +2,11 @@ package test
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"math/rand"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
@@ -45,16 +47,19 @@ type Env struct {
 	CLMock *clmock.CLMocker
 
 	// Client parameters used to launch the default client
-	Genesis      client.Genesis
-	ForkConfig   *config.ForkConfig
-	ClientParams hivesim.Params
-	ClientFiles  hivesim.Params
+	Genesis client.Genesis
+	// Genesis of every client type of the simulation, used to launch secondary clients
+	// of a different type than the client under test
+	ClientGeneses map[string]client.Genesis
+	ForkConfig    *config.ForkConfig
+	ClientParams  hivesim.Params
+	ClientFiles   hivesim.Params
 
 	// Sets the type of transactions to use during the test
 	TestTransactionType helper.TestTransactionType
 }
 
-func Run(testSpec Spec, ttd *big.Int, timeout time.Duration, t *hivesim.T, c *hivesim.Client, genesis client.Genesis, randSource *rand.Rand, cParams hivesim.Params, cFiles hivesim.Params) {
+func Run(testSpec Spec, ttd *big.Int, timeout time.Duration, t *hivesim.T, c *hivesim.Client, genesis client.Genesis, clientGeneses map[string]client.Genesis, randSource *rand.Rand, cParams hivesim.Params, cFiles hivesim.Params) {
 	// Setup the CL Mocker for this test
 	forkConfig := testSpec.GetForkConfig()
 	clMocker := clmock.NewCLMocker(
@@ -94,6 +99,7 @@ func Run(testSpec Spec, ttd *big.Int, timeout time.Duration, t *hivesim.T, c *hi
 		HiveEngine:          ec,
 		CLMock:              clMocker,
 		Genesis:             genesis,
+		ClientGeneses:       clientGeneses,
 		ForkConfig:          forkConfig,
 		ClientParams:        cParams,
 		ClientFiles:         cFiles,
@@ -149,6 +155,31 @@ func (t *Env) MainTTD() *big.Int {
 	return t.Engine.TerminalTotalDifficulty()
 }
 
+// SecondaryClientType returns a client type of the simulation that is different from
+// the type of the client under test. It returns an error if there is no other client
+// type available.
+func (t *Env) SecondaryClientType() (string, error) {
+	clientTypes := make([]string, 0, len(t.ClientGeneses))
+	for clientType := range t.ClientGeneses {
+		clientTypes = append(clientTypes, clientType)
+	}
+	sort.Strings(clientTypes)
+	for _, clientType := range clientTypes {
+		if clientType != t.Client.Type {
+			return clientType, nil
+		}
+	}
+	return "", fmt.Errorf("no client type other than %s available", t.Client.Type)
+}
+
+// ClientGenesis returns the genesis to launch a client of the given type with.
+func (t *Env) ClientGenesis(clientType string) client.Genesis {
+	if genesis, ok := t.ClientGeneses[clientType]; ok {
+		return genesis
+	}
+	return t.Genesis
+}
+
 func (t *Env) HandleClientPostRunVerification(ec client.EngineClient) {
 	if err := ec.PostRunVerifications(); err != nil {
 		t.Fatalf("FAIL (%s): Client failed post-run verification: %v", t.TestName, err)
@@ -286,12 +286,31 @@ func (tec *TestEngineClient) TestEngineNewPayloadV3(payload *typ.ExecutableData)
 	return ret
 }
 
+func (tec *TestEngineClient) TestEngineNewPayloadV4(payload *typ.ExecutableData) *NewPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	status, err := tec.Engine.NewPayloadV4(ctx, payload)
+	ret := &NewPayloadResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: tec.Env},
+		Payload:   payload,
+		Status:    status,
+		Version:   4,
+		Error:     err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
 func (tec *TestEngineClient) TestEngineNewPayload(payload *typ.ExecutableData) *NewPayloadResponseExpectObject {
 	if payload == nil {
 		panic("Payload is nil")
 	}
 	version := tec.EngineAPIVersionResolver.NewPayloadVersion(payload.Timestamp)
-	if version == 3 {
+	if version == 4 {
+		return tec.TestEngineNewPayloadV4(payload)
+	} else if version == 3 {
 		return tec.TestEngineNewPayloadV3(payload)
 	} else if version == 2 {
 		return tec.TestEngineNewPayloadV2(payload)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
@@ -197,21 +198,37 @@ func (s BaseSpec) GetForkConfig() *config.ForkConfig {
 	} else if mainFork == config.Cancun {
 		forkConfig.ShanghaiTimestamp = new(big.Int).SetUint64(previousForkTime)
 		forkConfig.CancunTimestamp = new(big.Int).SetUint64(forkTime)
+	} else if mainFork == config.Prague {
+		forkConfig.ShanghaiTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.CancunTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.PragueTimestamp = new(big.Int).SetUint64(forkTime)
 	} else {
 		panic(fmt.Errorf("unknown fork: %s", mainFork))
 	}
 	return &forkConfig
 }
 
+// genesisFormats maps client names to the genesis format used by the client.
+var genesisFormats = map[string]func() client.Genesis{
+	"erigon":     func() client.Genesis { return &client.ErigonGenesis{} },
+	"nethermind": func() client.Genesis { return &client.NethermindChainSpec{} },
+}
+
+// HasGenesisFormat reports whether the genesis format of the given client is known.
+// Tests can only run on clients with a known genesis format.
+func HasGenesisFormat(clientName string) bool {
+	_, ok := genesisFormats[clientName]
+	return ok
+}
+
+// GenesisFactory creates an empty genesis in the format of the given client. The
+// client name has a trailing underscore, e.g. "erigon_".
 func GenesisFactory(clientName string) client.Genesis {
-	switch clientName {
-	case "erigon_":
-		return &client.ErigonGenesis{}
-	case "nethermind_":
-		return &client.NethermindChainSpec{}
-	default:
+	newGenesis, ok := genesisFormats[strings.TrimSuffix(clientName, "_")]
+	if !ok {
 		panic("unsupported client provided")
 	}
+	return newGenesis()
 }
 
 func (s BaseSpec) GetGenesis(base string) client.Genesis {
@@ -137,6 +137,7 @@ type ExecutableData struct {
 	// NewPayload parameters
 	VersionedHashes       *[]common.Hash `json:"-"`
 	ParentBeaconBlockRoot *common.Hash   `json:"-"`
+	ExecutionRequests     *[][]byte      `json:"-"`
 
 	// Payload Attributes used to build the block
 	PayloadAttributes PayloadAttributes `json:"-"`
@@ -163,10 +164,12 @@ type ExecutionPayloadEnvelope struct {
 	BlockValue            *big.Int        `json:"blockValue"             gencodec:"required"`
 	BlobsBundle           *BlobsBundle    `json:"blobsBundle,omitempty"`
 	ShouldOverrideBuilder *bool           `json:"shouldOverrideBuilder,omitempty"`
+	ExecutionRequests     [][]byte        `json:"executionRequests,omitempty"`
 }
 
 type executionPayloadEnvelopeMarshaling struct {
-	BlockValue *hexutil.Big
+	BlockValue        *hexutil.Big
+	ExecutionRequests []hexutil.Bytes
 }
 
 // Convert Execution Payload Types
@@ -2,7 +2,7 @@
 
 wd="$(pwd)"
 cd ../../..
-go build ./cmd/hivechain
+go build -ldflags=-checklinkname=0 ./cmd/hivechain
 ./hivechain generate \
     -outdir "$wd/chain" \
     -length 2000 \